@@ -39,24 +39,81 @@ func processOptions() (rURL string, rVerbose bool) {
 	}
 	flag.CommandLine.BoolVar(&opts.Cookies, `bc`, opts.Cookies, s)
 
+	flag.CommandLine.StringVar(&opts.ConsentFile, `bb`, opts.ConsentFile,
+		"name of text-file with rules to suppress cookie-consent banners\n")
+
+	flag.CommandLine.StringVar(&opts.BlockFile, `bf`, opts.BlockFile,
+		"name of text-file with rules which requests to block\n")
+
+	flag.CommandLine.StringVar(&opts.CookieFile, `bj`, opts.CookieFile,
+		"name of cookies.txt or JSON file with cookies to load\n")
+
+	flag.CommandLine.StringVar(&opts.CredentialsFile, `ba`, opts.CredentialsFile,
+		"name of text-file with credentials for HTTP authentication\n")
+
+	flag.CommandLine.StringVar(&opts.AcceptLanguage, `bl`, opts.AcceptLanguage,
+		"value of the Accept-Language header to send (e.g. 'de,en;q=0.8')\n")
+
 	s = `skip sites with Certificate errors`
 	if !opts.CertErrors {
 		s += ` (default false)`
 	}
 	flag.CommandLine.BoolVar(&opts.CertErrors, `be`, opts.CertErrors, s)
 
+	flag.CommandLine.StringVar(&opts.ChromeFlags, `bg`, opts.ChromeFlags,
+		"additional command-line flags for the local browser\n(e.g. '--no-sandbox --disable-gpu')\n")
+
+	flag.CommandLine.StringVar(&opts.StylesFile, `bh`, opts.StylesFile,
+		"name of text-file that lists styles to add and elements to hide\n")
+
+	flag.CommandLine.StringVar(&opts.HideSelectors, `bi`, opts.HideSelectors,
+		"comma separated list of CSS selectors of elements to hide\n")
+
+	flag.CommandLine.StringVar(&opts.ProfileDir, `bd`, opts.ProfileDir,
+		"directory of persistent browser profile(s)\n")
+
 	s = `let browser emulate a mobile device`
 	if !opts.Mobile {
 		s += ` (default false)`
 	}
 	flag.CommandLine.BoolVar(&opts.Mobile, `bm`, opts.Mobile, s)
 
+	flag.CommandLine.IntVar((*int)(&opts.ProfileMode), `bp`, int(opts.ProfileMode),
+		"browser profile to use:\n0 = ephemeral, 1 = shared, 2 = per host")
+
+	flag.CommandLine.StringVar(&opts.ProfilesFile, `bu`, opts.ProfilesFile,
+		"name of JSON file with per-host capture profiles\n")
+
+	s = `run the local browser headless, i.e. without window`
+	if opts.Headless {
+		s += ` (default true)`
+	}
+	flag.CommandLine.BoolVar(&opts.Headless, `bn`, opts.Headless, s)
+
 	s = `let browser show scrollbars if available`
 	if !opts.Scrollbars {
 		s += ` (default false)`
 	}
 	flag.CommandLine.BoolVar(&opts.Scrollbars, `bs`, opts.Scrollbars, s)
 
+	s = `reuse supervised browsers for the captures`
+	if !opts.BrowserReuse {
+		s += ` (default false)`
+	}
+	flag.CommandLine.BoolVar(&opts.BrowserReuse, `br`, opts.BrowserReuse, s)
+
+	flag.CommandLine.IntVar(&opts.BrowserRecycle, `bk`, opts.BrowserRecycle,
+		"number of captures after which the reused browser is restarted\n(0 = never)")
+
+	flag.CommandLine.IntVar(&opts.BrowserMaxRSS, `bo`, opts.BrowserMaxRSS,
+		"max. memory (MB) of the reused browser before it's restarted\n(0 = unlimited)")
+
+	flag.CommandLine.StringVar(&opts.RemoteURL, `bw`, opts.RemoteURL,
+		"DevTools URL of a running browser to use (e.g. 'ws://127.0.0.1:9222/')\n")
+
+	flag.CommandLine.StringVar(&opts.ExecPath, `bx`, opts.ExecPath,
+		"path of the local browser executable\n")
+
 	flag.CommandLine.IntVar(&opts.MaxProcessTime, `bt`, opts.MaxProcessTime,
 		"max. time (seconds) allowed to process a single web page")
 
@@ -68,18 +125,39 @@ func processOptions() (rURL string, rVerbose bool) {
 	}
 	flag.CommandLine.BoolVar(&opts.AcceptOther, `ia`, opts.AcceptOther, s)
 
+	flag.CommandLine.IntVar(&opts.BlankRatio, `ib`, opts.BlankRatio,
+		"percentage of same-coloured pixels marking an image as blank\n(0 = no check)")
+
+	flag.CommandLine.IntVar(&opts.BlankTolerance, `ic`, opts.BlankTolerance,
+		"max. colour difference of pixels considered the same colour")
+
 	flag.CommandLine.StringVar(&opts.ImageDir, `id`, opts.ImageDir,
 		"directory for storing the screenshot image")
 
+	flag.CommandLine.IntVar((*int)(&opts.ErrorPages), `ie`, int(opts.ErrorPages),
+		"handling of HTTP error pages:\n0 = fail, 1 = save separately, 2 = save with short TTL")
+
 	flag.CommandLine.IntVar(&opts.ImageHeight, `ih`, opts.ImageHeight,
 		"max. height of the screenshot image")
 
+	s = `write a JSON sidecar file describing the image`
+	if !opts.Sidecar {
+		s += ` (default false)`
+	}
+	flag.CommandLine.BoolVar(&opts.Sidecar, `ij`, opts.Sidecar, s)
+
+	flag.CommandLine.StringVar(&opts.MimePolicy, `im`, opts.MimePolicy,
+		"comma separated list of 'MIME-type=action' entries overriding the built-in policy\n(actions: convert, download, reject, render)")
+
 	s = `overwrite an existing image`
 	if !opts.ImageOverwrite {
 		s += ` (default false)`
 	}
 	flag.CommandLine.BoolVar(&opts.ImageOverwrite, `io`, opts.ImageOverwrite, s)
 
+	flag.CommandLine.IntVar((*int)(&opts.PreviewSource), `ip`, int(opts.PreviewSource),
+		"source of the preview image:\n0 = screenshot, 1 = og:image,\n2 = og:image or screenshot, 3 = screenshot or og:image")
+
 	flag.CommandLine.IntVar(&opts.ImageQuality, `iq`, opts.ImageQuality,
 		"quality of the screenshot image")
 
@@ -89,6 +167,9 @@ func processOptions() (rURL string, rVerbose bool) {
 	}
 	flag.CommandLine.Float64Var(&opts.ImageScale, `is`, opts.ImageScale, s)
 
+	flag.CommandLine.IntVar(&opts.ErrorTTL, `it`, opts.ErrorTTL,
+		"time (minutes) after which a saved error page gets replaced")
+
 	flag.CommandLine.IntVar(&opts.ImageWidth, `iw`, opts.ImageWidth,
 		"max. width of the screenshot image")
 
@@ -97,6 +178,12 @@ func processOptions() (rURL string, rVerbose bool) {
 	flag.CommandLine.StringVar(&opts.HostsAvoidJSfile, `ja`, opts.HostsAvoidJSfile,
 		"name of text-file that contains sites better avoiding JavaScript\n")
 
+	flag.CommandLine.StringVar(&opts.ScriptsFile, `jf`, opts.ScriptsFile,
+		"name of text-file that lists scripts to run in web pages\n")
+
+	flag.CommandLine.StringVar(&opts.HostsLearnJSfile, `jl`, opts.HostsLearnJSfile,
+		"name of text-file to record sites learned to need JavaScript\n")
+
 	flag.CommandLine.StringVar(&opts.HostsNeedJSfile, `jn`, opts.HostsNeedJSfile,
 		"name of text-file that contains sites needing JavaScript\n")
 
@@ -112,6 +199,47 @@ func processOptions() (rURL string, rVerbose bool) {
 	flag.CommandLine.StringVar(&opts.UserAgent, `ju`, opts.UserAgent,
 		"description of the UserAgent the browser should report\n")
 
+	// --- proxy related settings:
+
+	flag.CommandLine.StringVar(&opts.ProxyBypass, `pb`, opts.ProxyBypass,
+		"comma separated list of hosts/domains to access without proxy\n")
+
+	flag.CommandLine.StringVar(&opts.ProxyFile, `pf`, opts.ProxyFile,
+		"name of text-file selecting the proxy for certain hosts\n")
+
+	flag.CommandLine.StringVar(&opts.Proxy, `pp`, opts.Proxy,
+		"URL of the proxy to use (e.g. 'http://proxy:3128', 'socks5://host:1080')\n")
+
+	// --- retry related settings:
+
+	flag.CommandLine.IntVar(&opts.RetryAttempts, `ra`, opts.RetryAttempts,
+		"max. number of attempts for a failing capture")
+
+	flag.CommandLine.IntVar(&opts.RetryDelay, `rd`, opts.RetryDelay,
+		"delay (milliseconds) before the first retry")
+
+	flag.CommandLine.StringVar(&opts.RetryOn, `ro`, opts.RetryOn,
+		"comma separated list of error classes to retry\n(browser, network, server, timeout)")
+
+	// --- URL related settings:
+
+	flag.CommandLine.StringVar(&opts.CanonicalParams, `uq`, opts.CanonicalParams,
+		"comma separated list of query parameters to ignore for the image cache\n")
+
+	s = `use the same cached image for 'http' and 'https' URLs`
+	if !opts.CanonicalScheme {
+		s += ` (default false)`
+	}
+	flag.CommandLine.BoolVar(&opts.CanonicalScheme, `us`, opts.CanonicalScheme, s)
+
+	// --- wait related settings:
+
+	flag.CommandLine.StringVar(&opts.WaitSelector, `ws`, opts.WaitSelector,
+		"CSS selector of an element to wait for before the screenshot\n")
+
+	flag.CommandLine.IntVar(&opts.WaitTime, `wt`, opts.WaitTime,
+		"time (milliseconds) to wait for a page to render\n(0 = 2 seconds, 4 with JavaScript)")
+
 	// --- general options:
 
 	flag.CommandLine.StringVar(&rURL, `u`, rURL,
@@ -153,6 +281,7 @@ func exit(aText string, aHelp, isVerbose bool, aCode int) {
 	} else if aHelp {
 		showHelp()
 	}
+	screenshot.CloseBrowser()
 
 	os.Exit(aCode)
 } // exit()
@@ -0,0 +1,358 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"crypto/md5" // #nosec G501 – required by the Digest scheme
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+The credentials file is a host rules file (see `hostrules.go`) with
+the action
+
+	auth  USER:PASSWORD
+
+providing the credentials to answer HTTP authentication challenges
+(`Basic` or `Digest`) of the respective host.
+
+Example:
+
+	intranet.example.com  auth  reader:secret
+*/
+
+const (
+	// Rule keyword for credentials:
+	actionAuth = `auth`
+
+	// Max. number of Digest nonces to keep track of:
+	maxNonces = 1024
+)
+
+type (
+	// TCredentialsFunc returns the credentials to use for HTTP
+	// authentication with `aHost`.
+	//
+	// An empty `rUser` means there are no credentials for `aHost`.
+	TCredentialsFunc func(aHost string) (rUser, rPassword string)
+
+	// `tNonceCounts` counts the requests sent with the servers'
+	// Digest nonces.
+	tNonceCounts struct {
+		sync.Mutex
+
+		// The number of uses by nonce:
+		counts map[string]uint32
+	}
+)
+
+var (
+	// The optional callback providing credentials:
+	ssCredentialsFunc TCredentialsFunc
+
+	// The rules of the credentials file:
+	ssCredentials = tHostRules{
+		kind: RulesCredentials,
+	}
+
+	// The uses of the Digest nonces:
+	ssNonces tNonceCounts
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `authHeader()` returns the `Authorization` header answering the
+// challenge of `aResponse` for `aRequest`.
+//
+// If the server offers several schemes the strongest supported one
+// (see `challengeStrength()`) is used.
+//
+// Parameters:
+//   - `aRequest`: The request which was challenged.
+//   - `aResponse`: The server's `401` response.
+//
+// Returns:
+//   - `string`: The header value or an empty string if not available.
+func authHeader(aRequest *http.Request, aResponse *http.Response) string {
+	user, password := credentials(aRequest.URL.Hostname())
+	if 0 == len(user) {
+		return ""
+	}
+
+	var (
+		best       map[string]string
+		bestScheme string
+		strength   int
+	)
+	for _, challenge := range aResponse.Header.Values("WWW-Authenticate") {
+		scheme, params := parseChallenge(challenge)
+		if s := challengeStrength(scheme, params); s > strength {
+			best, bestScheme, strength = params, scheme, s
+		}
+	}
+
+	switch bestScheme {
+	case "basic":
+		aRequest.SetBasicAuth(user, password)
+		return aRequest.Header.Get("Authorization")
+
+	case "digest":
+		result, err := digestAuth(best, aRequest.Method,
+			aRequest.URL.RequestURI(), user, password)
+		if nil != err {
+			log.Println(ssLibName, err)
+		}
+		return result
+	}
+
+	return ""
+} // authHeader()
+
+// `challengeStrength()` returns the strength of the authentication
+// challenge `aScheme` with `aParams`.
+//
+// Parameters:
+//   - `aScheme`: The (lowercased) authentication scheme.
+//   - `aParams`: The parameters of the challenge.
+//
+// Returns:
+//   - `int`: The challenge's strength; `0` if it's not supported.
+func challengeStrength(aScheme string, aParams map[string]string) int {
+	switch aScheme {
+	case "basic":
+		return 1
+
+	case "digest":
+		switch strings.ToUpper(aParams["algorithm"]) {
+		case "", "MD5":
+			return 2
+		case "SHA-256":
+			return 3
+		}
+	}
+
+	return 0
+} // challengeStrength()
+
+// `credentials()` returns the credentials to use for `aHost`.
+//
+// The [SetCredentialsFunc] callback takes precedence over the
+// [SetCredentialsFile] rules.
+//
+// Parameters:
+//   - `aHost`: The host asking for authentication.
+//
+// Returns:
+//   - `string`: The username (empty if not available).
+//   - `string`: The password.
+func credentials(aHost string) (string, string) {
+	aHost = normaliseHost(aHost)
+	if nil != ssCredentialsFunc {
+		if user, password := ssCredentialsFunc(aHost); 0 < len(user) {
+			return user, password
+		}
+	}
+
+	for _, rule := range ssCredentials.rules() {
+		if (actionAuth == rule.action) && rule.pattern.matches(aHost, "", "") {
+			if user, password, ok := strings.Cut(rule.arg, ":"); ok {
+				return user, password
+			}
+		}
+	}
+
+	return "", ""
+} // credentials()
+
+// `digestAuth()` returns the `Authorization` header answering the
+// `Digest` challenge `aParams`.
+//
+// Parameters:
+//   - `aParams`: The parameters of the server's challenge.
+//   - `aMethod`: The request's method.
+//   - `aURI`: The request's URI.
+//   - `aUser`: The username to use.
+//   - `aPassword`: The password to use.
+//
+// Returns:
+//   - `string`: The header value or an empty string if the challenge isn't supported.
+//   - `error`: A possible error creating the client nonce.
+func digestAuth(aParams map[string]string, aMethod, aURI, aUser, aPassword string) (string, error) {
+	var newHash func() hash.Hash
+	algorithm := aParams["algorithm"]
+	switch strings.ToUpper(algorithm) {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", nil // unsupported algorithm
+	}
+	hexHash := func(aData string) string {
+		h := newHash()
+		h.Write([]byte(aData))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	realm, nonce := aParams["realm"], aParams["nonce"]
+	ha1 := hexHash(aUser + ":" + realm + ":" + aPassword)
+	ha2 := hexHash(aMethod + ":" + aURI)
+
+	var sb strings.Builder
+	sb.WriteString(`Digest username="` + aUser + `", realm="` + realm +
+		`", nonce="` + nonce + `", uri="` + aURI + `"`)
+	if 0 < len(algorithm) {
+		sb.WriteString(`, algorithm=` + algorithm)
+	}
+	if opaque, ok := aParams["opaque"]; ok {
+		sb.WriteString(`, opaque="` + opaque + `"`)
+	}
+
+	qop := ""
+	for _, q := range strings.Split(aParams["qop"], ",") {
+		if "auth" == strings.TrimSpace(q) {
+			qop = "auth"
+		}
+	}
+	if 0 == len(qop) {
+		sb.WriteString(`, response="` + hexHash(ha1+":"+nonce+":"+ha2) + `"`)
+		return sb.String(), nil
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); nil != err {
+		return "", err
+	}
+	cnonce, nc := hex.EncodeToString(buf), fmt.Sprintf("%08x", ssNonces.next(nonce))
+	sb.WriteString(`, qop=auth, nc=` + nc + `, cnonce="` + cnonce +
+		`", response="` + hexHash(ha1+":"+nonce+":"+nc+":"+cnonce+":auth:"+ha2) + `"`)
+
+	return sb.String(), nil
+} // digestAuth()
+
+// `parseChallenge()` splits a `WWW-Authenticate` header into its
+// scheme and parameters.
+//
+// Parameters:
+//   - `aChallenge`: The header value to parse.
+//
+// Returns:
+//   - `string`: The (lowercased) authentication scheme.
+//   - `map[string]string`: The challenge's parameters.
+func parseChallenge(aChallenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(aChallenge), " ")
+	params := make(map[string]string)
+
+	for rest = strings.TrimSpace(rest); 0 < len(rest); rest = strings.TrimSpace(rest) {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if strings.HasPrefix(value, `"`) {
+			// quoted string, possibly containing commas:
+			end := strings.Index(value[1:], `"`)
+			if 0 > end {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = strings.TrimPrefix(strings.TrimSpace(value[end+2:]), ",")
+			continue
+		}
+
+		value, rest, _ = strings.Cut(value, ",")
+		params[key] = strings.TrimSpace(value)
+	}
+
+	return strings.ToLower(scheme), params
+} // parseChallenge()
+
+// `next()` returns the number of the next request using `aNonce`.
+//
+// Parameters:
+//   - `aNonce`: The server's nonce.
+//
+// Returns:
+//   - `uint32`: The nonce count to send (starting with `1`).
+func (nc *tNonceCounts) next(aNonce string) uint32 {
+	nc.Lock()
+	defer nc.Unlock()
+
+	if _, ok := nc.counts[aNonce]; !ok && (maxNonces <= len(nc.counts)) {
+		// Servers issue new nonces frequently, so forget the old ones:
+		nc.counts = nil
+	}
+	if nil == nc.counts {
+		nc.counts = make(map[string]uint32)
+	}
+	nc.counts[aNonce]++
+
+	return nc.counts[aNonce]
+} // next()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `CredentialsFile()` returns the name of the file containing the
+// credentials for HTTP authentication.
+//
+// Returns:
+//   - `string`: The path/file name of the credentials file.
+func CredentialsFile() string {
+	return ssOptions.CredentialsFile
+} // CredentialsFile()
+
+// `SetCredentialsFile()` configures the name of the file containing
+// the credentials for HTTP authentication (`Basic` or `Digest`).
+//
+// Each line of that file consists of a host/domain (or `*` for all
+// hosts), the keyword `auth`, and the credentials:
+//
+//	intranet.example.com  auth  reader:secret
+//
+// The credentials are used by the browser as well as for direct
+// downloads. Since the file contains passwords it should be readable
+// by its owner only.
+// An invalid filename disables the feature.
+//
+// See also [SetCredentialsFunc].
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the credentials file.
+func SetCredentialsFile(aFilename string) {
+	ssOptions.CredentialsFile = ssCredentials.setFile(aFilename)
+} // SetCredentialsFile()
+
+// `SetCredentialsFunc()` installs a callback providing the credentials
+// for HTTP authentication (`Basic` or `Digest`) with a given host.
+//
+// The callback takes precedence over the [SetCredentialsFile] rules;
+// if it returns an empty username the file is consulted.
+// Passing `nil` removes the callback.
+//
+// Parameters:
+//   - `aFunc`: The callback to use.
+func SetCredentialsFunc(aFunc TCredentialsFunc) {
+	ssCredentialsFunc = aFunc
+} // SetCredentialsFunc()
+
+/* _EoF_ */
@@ -0,0 +1,158 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_authHeader(t *testing.T) {
+	defer SetCredentialsFunc(nil)
+	SetCredentialsFunc(func(aHost string) (string, string) {
+		return "reader", "secret"
+	})
+
+	const (
+		basic  = `Basic realm="Intranet"`
+		digest = `Digest realm="Intranet", qop="auth", nonce="abc123"`
+		sha256 = `Digest realm="Intranet", qop="auth", nonce="abc123", algorithm=SHA-256`
+	)
+	tests := []struct {
+		name        string
+		aChallenges []string
+		want        string
+	}{
+		{"1", nil, ""},
+		{"2", []string{`Bearer realm="api"`}, ""},
+		{"3", []string{basic}, "Basic "},
+		{"4", []string{basic, digest}, "Digest "},
+		{"5", []string{digest, basic}, "Digest "},
+		{"6", []string{digest, sha256}, "algorithm=SHA-256"},
+		{"7", []string{`Digest realm="x", nonce="1", algorithm=SHA-512-256`, basic}, "Basic "},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, "https://intranet.example.com/", nil)
+			response := &http.Response{Header: http.Header{"Www-Authenticate": tt.aChallenges}}
+			got := authHeader(request, response)
+			if (0 == len(tt.want)) != (0 == len(got)) || !strings.Contains(got, tt.want) {
+				t.Errorf("%q: authHeader() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_authHeader()
+
+func Test_credentials(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.auth"
+	list := `
+intranet.example.com	auth	reader:Secret Pass
+example.org	auth	invalid
+`
+	writeFile(fName, []byte(list), nil)
+	defer func() {
+		_ = os.Remove(fName)
+		SetCredentialsFile("")
+		SetCredentialsFunc(nil)
+	}()
+	SetCredentialsFile(fName)
+
+	tests := []struct {
+		name     string
+		aHost    string
+		aFunc    TCredentialsFunc
+		wantUser string
+		wantPass string
+	}{
+		{"1", "example.com", nil, "", ""},
+		{"2", "Intranet.Example.com", nil, "reader", "Secret Pass"},
+		{"3", "example.org", nil, "", ""},
+		{"4", "example.com", func(aHost string) (string, string) {
+			return "user@" + aHost, "pw"
+		}, "user@example.com", "pw"},
+		{"5", "intranet.example.com", func(aHost string) (string, string) {
+			return "", ""
+		}, "reader", "Secret Pass"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCredentialsFunc(tt.aFunc)
+			user, pass := credentials(tt.aHost)
+			if (user != tt.wantUser) || (pass != tt.wantPass) {
+				t.Errorf("%q: credentials() = %q, %q, want %q, %q",
+					tt.name, user, pass, tt.wantUser, tt.wantPass)
+			}
+		})
+	}
+} // Test_credentials()
+
+func Test_digestAuth(t *testing.T) {
+	ssNonces = tNonceCounts{}
+	params := map[string]string{"nonce": "abc123", "qop": "auth", "realm": "Intranet"}
+
+	tests := []struct {
+		name    string
+		aParams map[string]string
+		want    string
+	}{
+		{"1", params, "nc=00000001"},
+		{"2", params, "nc=00000002"},
+		{"3", map[string]string{"nonce": "xyz", "qop": "auth"}, "nc=00000001"},
+		{"4", map[string]string{"nonce": "xyz"}, `response="`},
+		{"5", map[string]string{"nonce": "xyz", "algorithm": "SHA-512"}, ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := digestAuth(tt.aParams, http.MethodGet, "/", "reader", "secret")
+			if nil != err {
+				t.Fatalf("%q: digestAuth() error = %v", tt.name, err)
+			}
+			if (0 == len(tt.want)) != (0 == len(got)) || !strings.Contains(got, tt.want) {
+				t.Errorf("%q: digestAuth() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_digestAuth()
+
+func Test_parseChallenge(t *testing.T) {
+	tests := []struct {
+		name       string
+		aChallenge string
+		wantScheme string
+		wantParams map[string]string
+	}{
+		{"1", `Basic realm="Intranet"`, "basic",
+			map[string]string{"realm": "Intranet"}},
+		{"2", `Digest realm="test, realm", qop="auth,auth-int", nonce="abc123", opaque=xyz`, "digest",
+			map[string]string{"realm": "test, realm", "qop": "auth,auth-int", "nonce": "abc123", "opaque": "xyz"}},
+		{"3", `Bearer`, "bearer", map[string]string{}},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, params := parseChallenge(tt.aChallenge)
+			if scheme != tt.wantScheme {
+				t.Errorf("%q: parseChallenge() scheme = %q, want %q",
+					tt.name, scheme, tt.wantScheme)
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("%q: parseChallenge() params = %v, want %v",
+					tt.name, params, tt.wantParams)
+			}
+		})
+	}
+} // Test_parseChallenge()
+
+/* _EoF_ */
@@ -0,0 +1,228 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"image"
+	"os"
+
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Max. number of pixels per dimension to examine in `isBlank()`:
+	blankSamples = 512
+
+	// JavaScript detecting the browser's own error page (like
+	// "This site can't be reached"):
+	errorPageScript = `(function() {
+	return ('chrome-error:' === location.protocol) ||
+		(null !== document.querySelector('body.neterror'));
+})()`
+)
+
+var (
+	// ErrBlankImage is returned (wrapped) if the rendered image is
+	// blank or broken (see [SetBlankRatio]).
+	ErrBlankImage = errors.New(ssLibName + ": blank or broken image")
+
+	// ErrChromeErrorPage is returned (wrapped) if the browser showed
+	// its own error page instead of the requested web page.
+	ErrChromeErrorPage = errors.New(ssLibName + ": browser error page")
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `errorPageTasks()` returns the browser actions failing if the browser
+// shows its own error page instead of the web page `aURL`.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform after loading the page.
+func errorPageTasks(aURL string) chromedp.Tasks {
+	return chromedp.Tasks{
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			var isError bool
+			if err := chromedp.Evaluate(errorPageScript, &isError).Do(aContext); (nil == err) && isError {
+				return errors.Join(ErrChromeErrorPage,
+					errors.New(ssLibName+": can't load '"+aURL+"'"))
+			}
+
+			return nil
+		}),
+	}
+} // errorPageTasks()
+
+// `isBlank()` returns whether `aImage` is blank, i.e. whether at least
+// [BlankRatio] percent of its pixels have (nearly) the same colour.
+//
+// This catches uniformly coloured images as well as mostly empty
+// renderings (like the browser's error pages). Large images are
+// examined on a regular grid of sample pixels.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aImage`: The image to check.
+//
+// Returns:
+//   - `bool`: Whether `aImage` is considered blank.
+func isBlank(aOptions *TScreenshotParams, aImage image.Image) bool {
+	if nil == aImage {
+		return true
+	}
+	if 0 >= aOptions.BlankRatio {
+		return false // check disabled
+	}
+	bounds := aImage.Bounds()
+	if bounds.Empty() {
+		return true
+	}
+	stepX := max(1, bounds.Dx()/blankSamples)
+	stepY := max(1, bounds.Dy()/blankSamples)
+
+	// Find the most frequent colour:
+	var samples []uint32
+	histogram := make(map[uint32]int)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, _ := aImage.At(x, y).RGBA()
+			rgb := (r>>8)<<16 | (g>>8)<<8 | b>>8
+			samples = append(samples, rgb)
+			histogram[rgb]++
+		}
+	}
+	var dominant uint32
+	for rgb, count := range histogram {
+		if count > histogram[dominant] {
+			dominant = rgb
+		}
+	}
+
+	// Count the pixels (nearly) matching the dominant colour:
+	similar := 0
+	for _, rgb := range samples {
+		if similarColour(rgb, dominant, aOptions.BlankTolerance) {
+			similar++
+		}
+	}
+
+	return similar*100 >= len(samples)*aOptions.BlankRatio
+} // isBlank()
+
+// `similarColour()` returns whether the colours `aRGB1` and `aRGB2`
+// differ by at most `aTolerance` (see [BlankTolerance]) in each channel.
+//
+// Parameters:
+//   - `aRGB1`: The first colour (as `0xRRGGBB`).
+//   - `aRGB2`: The second colour (as `0xRRGGBB`).
+//   - `aTolerance`: The max. difference per colour channel.
+//
+// Returns:
+//   - `bool`: Whether the colours are considered equal.
+func similarColour(aRGB1, aRGB2 uint32, aTolerance int) bool {
+	for shift := 0; 24 > shift; shift += 8 {
+		c1, c2 := int((aRGB1>>shift)&0xFF), int((aRGB2>>shift)&0xFF)
+		if c1-c2 > aTolerance || c2-c1 > aTolerance {
+			return false
+		}
+	}
+
+	return true
+} // similarColour()
+
+// `validImage()` returns whether the image file `aFilename` can be
+// decoded and isn't blank (see [SetBlankRatio]).
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aFilename`: The name of the image file to check.
+//
+// Returns:
+//   - `bool`: Whether the image file is usable.
+func validImage(aOptions *TScreenshotParams, aFilename string) bool {
+	file, err := os.Open(aFilename) // #nosec G304
+	if nil != err {
+		return false
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if nil != err {
+		return false
+	}
+
+	return !isBlank(aOptions, img)
+} // validImage()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `BlankRatio()` returns the percentage of same-coloured pixels above
+// which an image is considered blank.
+//
+// Returns:
+//   - `int`: The blank ratio (in percent).
+func BlankRatio() int {
+	return ssOptions.BlankRatio
+} // BlankRatio()
+
+// `SetBlankRatio()` configures the percentage of (nearly) same-coloured
+// pixels above which a screenshot is considered blank or broken.
+//
+// Such a screenshot is not saved; instead [Capture] returns an error
+// matching [ErrBlankImage]. The same check is applied to already
+// existing image files which are replaced if they're blank.
+// `100` rejects uniformly coloured images only, while `0` disables
+// the check. The default is `99`.
+//
+// Parameters:
+//   - `aPercent`: The blank ratio (in percent).
+func SetBlankRatio(aPercent int) {
+	if 0 > aPercent {
+		aPercent = 0
+	} else if 100 < aPercent {
+		aPercent = 100
+	}
+	ssOptions.BlankRatio = aPercent
+} // SetBlankRatio()
+
+// `BlankTolerance()` returns the max. difference of a colour channel
+// for two pixels to be considered of the same colour.
+//
+// Returns:
+//   - `int`: The colour tolerance.
+func BlankTolerance() int {
+	return ssOptions.BlankTolerance
+} // BlankTolerance()
+
+// `SetBlankTolerance()` configures the max. difference (`0` to `255`)
+// of each colour channel for two pixels to be considered of the same
+// colour when checking for blank images (see [SetBlankRatio]).
+//
+// A small tolerance accounts for compression artefacts and subtle
+// gradients. The default is `8`.
+//
+// Parameters:
+//   - `aTolerance`: The colour tolerance.
+func SetBlankTolerance(aTolerance int) {
+	if 0 > aTolerance {
+		aTolerance = 0
+	} else if 255 < aTolerance {
+		aTolerance = 255
+	}
+	ssOptions.BlankTolerance = aTolerance
+} // SetBlankTolerance()
+
+/* _EoF_ */
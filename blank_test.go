@@ -0,0 +1,84 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// `testImage()` returns a PNG encoded white image, optionally with
+// a black box covering a tenth of it.
+func testImage(withContent bool) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 200; x++ {
+			img.Set(x, y, color.White)
+			if withContent && (20 > x) {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	var buffer bytes.Buffer
+	_ = png.Encode(&buffer, img)
+
+	return buffer.Bytes()
+} // testImage()
+
+func Test_isBlank(t *testing.T) {
+	defer func(aRatio, aTolerance int) {
+		SetBlankRatio(aRatio)
+		SetBlankTolerance(aTolerance)
+	}(BlankRatio(), BlankTolerance())
+
+	blank, _ := png.Decode(bytes.NewReader(testImage(false)))
+	content, _ := png.Decode(bytes.NewReader(testImage(true)))
+
+	// Nearly white "noise" within the colour tolerance:
+	noisy := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			c := uint8(250 + (x+y)%6)
+			noisy.Set(x, y, color.RGBA{c, c, c, 255})
+		}
+	}
+
+	tests := []struct {
+		name       string
+		aImage     image.Image
+		aRatio     int
+		aTolerance int
+		want       bool
+	}{
+		{"1", nil, 99, 8, true},
+		{"2", image.NewRGBA(image.Rect(0, 0, 0, 0)), 99, 8, true},
+		{"3", image.NewRGBA(image.Rect(0, 0, 10, 10)), 99, 8, true},
+		{"4", blank, 99, 8, true},
+		{"5", content, 99, 8, false},
+		{"6", content, 90, 8, true},
+		{"7", content, 0, 8, false},
+		{"8", noisy, 99, 8, true},
+		{"9", noisy, 99, 2, false},
+		{"10", image.NewUniform(color.RGBA{0, 0, 128, 255}), 100, 0, true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetBlankRatio(tt.aRatio)
+			SetBlankTolerance(tt.aTolerance)
+			if got := isBlank(ssOptions, tt.aImage); got != tt.want {
+				t.Errorf("%q: isBlank() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_isBlank()
+
+/* _EoF_ */
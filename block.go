@@ -0,0 +1,525 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+The blocking rules file is a host rules file (see `hostrules.go`) with
+the actions
+
+	url   PATTERN
+	type  RESOURCETYPE
+	list  FILTERLIST
+
+`url` blocks all requests whose URL matches `PATTERN` (where `*` matches
+any number of characters and `?` exactly one), `type` blocks all requests
+of the given resource type (e.g. `media`, `font`, `script`), and `list`
+blocks all requests matched by the named filter list which can be either
+a hosts file (like those used by ad-blocking DNS servers) or an
+EasyList-style filter list.
+Relative list filenames are relative to the rules file's directory.
+
+Example:
+
+	example.com  type  media
+	*            url   *://*.doubleclick.net/*
+	*            list  easylist.txt
+*/
+
+const (
+	// Rule keyword for filter lists:
+	actionList = `list`
+
+	// Rule keyword for resource types:
+	actionType = `type`
+
+	// Rule keyword for URL patterns:
+	actionURL = `url`
+)
+
+type (
+	// `tBlockList` is a compiled filter list.
+	//
+	// To avoid matching each request against all URL patterns of a
+	// list (an EasyList has tens of thousands of them) the patterns
+	// are indexed by a token (see `filterTokens()`) which any URL
+	// matched by the pattern contains; only the patterns of the
+	// tokens found in a request's URL are checked then.
+	tBlockList struct {
+		// URL patterns without a token, checked for all requests:
+		generic []*regexp.Regexp
+
+		// Blocked hosts/domains (incl. their subdomains):
+		hosts map[string]bool
+
+		// Time of next reading the list file:
+		nextTime time.Time
+
+		// Blocked URL patterns by their token:
+		patterns map[string][]*regexp.Regexp
+	}
+
+	// `tBlockLists` caches the filter lists named in the blocking
+	// rules file.
+	tBlockLists struct {
+		sync.Mutex
+
+		// The compiled filter lists by their path/file name:
+		lists map[string]*tBlockList
+	}
+
+	// `tBlocker` decides which requests of a web page to block.
+	tBlocker struct {
+		// The filter lists (and URL patterns) to apply:
+		lists []*tBlockList
+
+		// The (lowercased) resource types to block:
+		types map[string]bool
+	}
+)
+
+var (
+	// Tokens found in almost every URL and hence useless as index:
+	ssBadTokens = map[string]bool{
+		"com": true, "http": true, "https": true, "js": true,
+		"net": true, "org": true, "www": true,
+	}
+
+	// Cache of the filter lists named in the blocking rules file:
+	ssBlockLists tBlockLists
+
+	// The rules of the blocking rules file:
+	ssBlocking = tHostRules{
+		kind: RulesBlock,
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `blockList()` returns the (cached) filter list `aFilename`, re-reading
+// it if [ReadWaitTime] has passed since it was last read.
+//
+// Parameters:
+//   - `aFilename`: The (possibly relative) path/file name of the list.
+//
+// Returns:
+//   - `*tBlockList`: The compiled filter list.
+func blockList(aFilename string) *tBlockList {
+	aFilename = relPath(ssBlocking.file(), aFilename)
+
+	ssBlockLists.Lock()
+	defer ssBlockLists.Unlock()
+
+	list, ok := ssBlockLists.lists[aFilename]
+	if ok && time.Now().Before(list.nextTime) {
+		return list
+	}
+
+	list = readBlockList(aFilename)
+	if 0 < ssReadWaitTime {
+		list.nextTime = time.Now().Add(time.Duration(ssReadWaitTime) * time.Minute)
+	}
+	if nil == ssBlockLists.lists {
+		ssBlockLists.lists = make(map[string]*tBlockList)
+	}
+	ssBlockLists.lists[aFilename] = list
+
+	return list
+} // blockList()
+
+// `filterTokens()` returns the tokens the EasyList-style filter
+// `aLine` can be indexed by.
+//
+// A token is a run of letters, digits and `%` which every URL matched
+// by the filter contains as a whole, i.e. it's delimited by other
+// characters (or the filter's anchors) but not by a `*` wildcard.
+//
+// Parameters:
+//   - `aLine`: The (trimmed) filter line.
+//
+// Returns:
+//   - `[]string`: The (lowercased) tokens of the filter.
+func filterTokens(aLine string) (rList []string) {
+	line, startAnchor := strings.CutPrefix(aLine, `||`)
+	if !startAnchor {
+		line, startAnchor = strings.CutPrefix(line, `|`)
+	}
+	line, endAnchor := strings.CutSuffix(strings.ToLower(line), `|`)
+
+	for start, end := nextToken(line, 0); start < len(line); start, end = nextToken(line, end) {
+		if 0 < start {
+			if '*' == line[start-1] {
+				continue
+			}
+		} else if !startAnchor {
+			continue // might be part of a longer token
+		}
+		if end < len(line) {
+			if '*' == line[end] {
+				continue
+			}
+		} else if !endAnchor {
+			continue // might be part of a longer token
+		}
+		if token := line[start:end]; !ssBadTokens[token] {
+			rList = append(rList, token)
+		}
+	}
+
+	return
+} // filterTokens()
+
+// `newBlocker()` returns the blocker for the web page `aURL` or `nil`
+// if there are no blocking rules for its host.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `*tBlocker`: The blocker to use for the page's requests.
+func newBlocker(aURL string) *tBlocker {
+	var (
+		patterns tBlockList
+		result   tBlocker
+	)
+	host, port, path := urlParts(aURL)
+
+	for _, rule := range ssBlocking.rules() {
+		if !rule.pattern.matches(host, port, path) {
+			continue
+		}
+		switch rule.action {
+		case actionList:
+			result.lists = append(result.lists, blockList(rule.arg))
+
+		case actionType:
+			if nil == result.types {
+				result.types = make(map[string]bool)
+			}
+			result.types[strings.ToLower(rule.arg)] = true
+
+		case actionURL:
+			patterns.generic = append(patterns.generic, wildcardRE(rule.arg))
+		}
+	}
+
+	if 0 < len(patterns.generic) {
+		result.lists = append(result.lists, &patterns)
+	}
+	if (0 == len(result.lists)) && (0 == len(result.types)) {
+		return nil
+	}
+
+	return &result
+} // newBlocker()
+
+// `nextToken()` returns the position of the next token (i.e. a run of
+// letters, digits and `%`) in `aText` starting at `aFrom`.
+//
+// Parameters:
+//   - `aText`: The (lowercased) text to search.
+//   - `aFrom`: The index to start the search at.
+//
+// Returns:
+//   - `int`: The token's start index (`len(aText)` if there's none).
+//   - `int`: The index following the token.
+func nextToken(aText string, aFrom int) (rStart, rEnd int) {
+	tokenChar := func(aChar byte) bool {
+		return (('a' <= aChar) && ('z' >= aChar)) ||
+			(('0' <= aChar) && ('9' >= aChar)) || ('%' == aChar)
+	}
+
+	rStart = aFrom
+	for (rStart < len(aText)) && !tokenChar(aText[rStart]) {
+		rStart++
+	}
+	rEnd = rStart
+	for (rEnd < len(aText)) && tokenChar(aText[rEnd]) {
+		rEnd++
+	}
+
+	return
+} // nextToken()
+
+// `parseFilter()` parses a single line of a hosts file or an
+// EasyList-style filter list.
+//
+// Of the EasyList syntax only blocking rules without options are
+// supported; comments, element hiding rules, exception rules and
+// rules with options (`$…`) are ignored.
+//
+// Parameters:
+//   - `aLine`: The (trimmed) line to parse.
+//
+// Returns:
+//   - `string`: The host/domain to block.
+//   - `*regexp.Regexp`: The URL pattern to block.
+func parseFilter(aLine string) (string, *regexp.Regexp) {
+	if (0 == len(aLine)) || strings.ContainsAny(aLine[0:1], `#![`) {
+		return "", nil // comment or header
+	}
+
+	// hosts file: `0.0.0.0 ads.example.com` or `ads.example.com`
+	if fields := strings.Fields(aLine); 1 < len(fields) {
+		if nil == net.ParseIP(fields[0]) || !strings.Contains(fields[1], `.`) {
+			return "", nil // invalid line or `localhost` etc.
+		}
+		return strings.ToLower(fields[1]), nil
+	}
+
+	if strings.HasPrefix(aLine, `@@`) || strings.Contains(aLine, `#`) ||
+		strings.Contains(aLine, `$`) {
+		return "", nil // exception, element hiding or options
+	}
+
+	if strings.HasPrefix(aLine, `||`) {
+		if host, ok := strings.CutSuffix(aLine[2:], `^`); ok &&
+			!strings.ContainsAny(host, `/*^|`) {
+			return strings.ToLower(host), nil // `||ads.example.com^`
+		}
+	} else if !strings.ContainsAny(aLine, `/*^|`) && strings.Contains(aLine, `.`) {
+		return strings.ToLower(aLine), nil // plain hosts file entry
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`(?i)`)
+	line := aLine
+	if strings.HasPrefix(line, `||`) {
+		sb.WriteString(`^[a-z][a-z0-9+.-]*://([^/?#]*\.)?`)
+		line = line[2:]
+	} else if strings.HasPrefix(line, `|`) {
+		sb.WriteString(`^`)
+		line = line[1:]
+	} else {
+		// an unanchored pattern matches anywhere anyway, and a
+		// leading `.*` slows down the regular expression:
+		line = strings.TrimLeft(line, `*`)
+	}
+	anchorEnd := strings.HasSuffix(line, `|`)
+	if line = strings.TrimSuffix(line, `|`); !anchorEnd {
+		line = strings.TrimRight(line, `*`)
+	}
+	for _, r := range line {
+		switch r {
+		case '*':
+			sb.WriteString(`.*`)
+		case '^': // separator character or end of address
+			sb.WriteString(`(?:[^\w.%-]|$)`)
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	if anchorEnd {
+		sb.WriteString(`$`)
+	}
+
+	re, err := regexp.Compile(sb.String())
+	if nil != err {
+		return "", nil
+	}
+
+	return "", re
+} // parseFilter()
+
+// `readBlockList()` reads the named hosts file or EasyList-style
+// filter list.
+//
+// Each URL pattern is indexed by the least frequent of its tokens
+// (see `filterTokens()`) to keep the number of patterns to check
+// for a request small.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to read.
+//
+// Returns:
+//   - `*tBlockList`: The compiled filter list.
+func readBlockList(aFilename string) *tBlockList {
+	result := &tBlockList{
+		hosts:    make(map[string]bool),
+		patterns: make(map[string][]*regexp.Regexp),
+	}
+
+	data, err := os.ReadFile(aFilename) // #nosec G304
+	if (nil != err) || (0 == len(data)) {
+		return result
+	}
+
+	type tFilter struct {
+		re     *regexp.Regexp
+		tokens []string
+	}
+	var filters []tFilter
+	counts := make(map[string]int)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		host, re := parseFilter(line)
+		if 0 < len(host) {
+			result.hosts[host] = true
+		} else if nil != re {
+			filter := tFilter{re: re, tokens: filterTokens(line)}
+			for _, token := range filter.tokens {
+				counts[token]++
+			}
+			filters = append(filters, filter)
+		}
+	}
+
+	for _, filter := range filters {
+		if 0 == len(filter.tokens) {
+			result.generic = append(result.generic, filter.re)
+			continue
+		}
+		token := filter.tokens[0]
+		for _, t := range filter.tokens[1:] {
+			if counts[t] < counts[token] {
+				token = t
+			}
+		}
+		result.patterns[token] = append(result.patterns[token], filter.re)
+	}
+
+	return result
+} // readBlockList()
+
+// `wildcardRE()` returns a regular expression matching the same URLs
+// as the wildcard pattern `aPattern`.
+//
+// Parameters:
+//   - `aPattern`: The pattern with `*` (any characters) and `?` (one character) wildcards.
+//
+// Returns:
+//   - `*regexp.Regexp`: The compiled pattern.
+func wildcardRE(aPattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(aPattern)
+	expr = strings.ReplaceAll(expr, `\*`, `.*`)
+	expr = strings.ReplaceAll(expr, `\?`, `.`)
+
+	return regexp.MustCompile(`^` + expr + `$`)
+} // wildcardRE()
+
+// `blocks()` returns whether `aURL` is matched by the filter list.
+//
+// Parameters:
+//   - `aURL`: The URL to check.
+//
+// Returns:
+//   - `bool`: Whether to block the request of `aURL`.
+func (bl *tBlockList) blocks(aURL string) bool {
+	if 0 < len(bl.hosts) {
+		// Check the host and all its parent domains:
+		for host := hostOf(aURL); 0 < len(host); {
+			if bl.hosts[host] {
+				return true
+			}
+			idx := strings.IndexByte(host, '.')
+			if 0 > idx {
+				break
+			}
+			host = host[idx+1:]
+		}
+	}
+
+	for _, re := range bl.generic {
+		if re.MatchString(aURL) {
+			return true
+		}
+	}
+
+	if 0 < len(bl.patterns) {
+		// Check just the patterns indexed by the URL's tokens:
+		url := strings.ToLower(aURL)
+		for start, end := nextToken(url, 0); start < len(url); start, end = nextToken(url, end) {
+			for _, re := range bl.patterns[url[start:end]] {
+				if re.MatchString(aURL) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+} // blocks()
+
+// `blocks()` returns whether the request of `aURL` should be blocked.
+//
+// Parameters:
+//   - `aURL`: The requested URL.
+//   - `aType`: The resource type of the request.
+//
+// Returns:
+//   - `bool`: Whether to block the request.
+func (bl *tBlocker) blocks(aURL string, aType network.ResourceType) bool {
+	if bl.types[strings.ToLower(string(aType))] {
+		return true
+	}
+	for _, list := range bl.lists {
+		if list.blocks(aURL) {
+			return true
+		}
+	}
+
+	return false
+} // blocks()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `BlockFile()` returns the name of the file containing the rules
+// which requests of a web page to block.
+//
+// Returns:
+//   - `string`: The path/file name of the blocking rules.
+func BlockFile() string {
+	return ssOptions.BlockFile
+} // BlockFile()
+
+// `SetBlockFile()` configures the name of the file containing the
+// rules which requests of a web page to block (e.g. ads and trackers).
+//
+// Each line of that file consists of a host/domain (or `*` for all
+// hosts), the keyword `url`, `type`, or `list`, and its argument:
+//
+//	example.com  type  media
+//	*            url   *://*.doubleclick.net/*
+//	*            list  easylist.txt
+//
+// `url` blocks requests whose URL matches the given pattern (`*`
+// matches any number of characters, `?` a single one), `type` blocks
+// requests of the given resource type (like `font`, `image`, `media`,
+// `script`, or `stylesheet`), and `list` blocks the requests matched
+// by a hosts file or an EasyList-style filter list.
+// The files are re-read according to the [ReadWaitTime] setting, and
+// the number of blocked requests is reported by [Capture] in the
+// `BlockedRequests` field of its result.
+//
+// NOTE: The web page itself is never blocked.
+// An invalid filename disables the feature.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the blocking rules.
+func SetBlockFile(aFilename string) {
+	ssBlockLists.Lock()
+	ssBlockLists.lists = nil
+	ssBlockLists.Unlock()
+	ssOptions.BlockFile = ssBlocking.setFile(aFilename)
+} // SetBlockFile()
+
+/* _EoF_ */
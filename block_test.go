@@ -0,0 +1,294 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// `testFilterList()` returns an EasyList-style filter list with
+// `aCount` rules resembling those of a real EasyList.
+func testFilterList(aCount int) string {
+	var sb strings.Builder
+	sb.WriteString("[Adblock Plus 2.0]\n! Title: Crash Test Dummies\n")
+	for i := 0; i < aCount; i++ {
+		switch i % 8 {
+		case 0, 1, 2, 3:
+			fmt.Fprintf(&sb, "||ads%d.example.net^\n", i)
+		case 4:
+			fmt.Fprintf(&sb, "/banner%d/*\n", i)
+		case 5:
+			fmt.Fprintf(&sb, "-ad-%dx90.\n", i)
+		case 6:
+			fmt.Fprintf(&sb, "||cdn%d.example.org/track/\n", i)
+		default:
+			if 7 == i%1000 {
+				fmt.Fprintf(&sb, "*/ad%d*\n", i)
+			} else {
+				fmt.Fprintf(&sb, "&adzone%d=\n", i)
+			}
+		}
+	}
+
+	return sb.String()
+} // testFilterList()
+
+// Some URLs requested by a web page:
+var testRequests = []string{
+	"https://www.example.com/",
+	"https://www.example.com/assets/app.js?v=1234",
+	"https://www.example.com/images/banner12/top.png",
+	"https://static.example.com/css/site.css",
+	"https://img.example.com/ad-13x90.gif",
+	"https://cdn14.example.org/track/pixel.gif?id=42",
+	"https://ads16.example.net/show?slot=1",
+	"https://tracker.example.net/collect?adzone15=top&page=1",
+	"https://fonts.example.com/roboto-regular.woff2",
+}
+
+func Benchmark_tBlockList_blocks(b *testing.B) {
+	const lName = "./Crash_Test_Dummies.bench.list"
+	writeFile(lName, []byte(testFilterList(50000)), nil)
+	defer func() {
+		_ = os.Remove(lName)
+	}()
+	list := readBlockList(lName)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, url := range testRequests {
+			_ = list.blocks(url)
+		}
+	}
+} // Benchmark_tBlockList_blocks()
+
+func Test_filterTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		aLine string
+		want  string
+	}{
+		{"1", "||example.com/ads/", "example,ads"},
+		{"2", "/banner/*/img^", "banner,img"},
+		{"3", "|https://ads.", "ads"},
+		{"4", "-ad-300x250.", "ad,300x250"},
+		{"5", "*/ads*", ""},
+		{"6", "adbanner", ""},
+		{"7", "|AdBanner|", "adbanner"},
+		{"8", "&adzone=", "adzone"},
+		{"9", "||www.example.com^", "example"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(filterTokens(tt.aLine), ","); got != tt.want {
+				t.Errorf("%q: filterTokens() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_filterTokens()
+
+func Test_tBlockList_blocks(t *testing.T) {
+	const lName = "./Crash_Test_Dummies.tokens.list"
+	data := testFilterList(2000)
+	writeFile(lName, []byte(data), nil)
+	defer func() {
+		_ = os.Remove(lName)
+	}()
+	list := readBlockList(lName)
+
+	// The indexed patterns must block the same requests as
+	// checking all patterns one by one:
+	for _, url := range append(testRequests, "https://www.example.com/ad1000/x.png") {
+		want := false
+		for _, line := range strings.Split(data, "\n") {
+			host, re := parseFilter(line)
+			if ((0 < len(host)) && (hostOf(url) == host)) ||
+				((nil != re) && re.MatchString(url)) {
+				want = true
+				break
+			}
+		}
+		if got := list.blocks(url); got != want {
+			t.Errorf("%q: tBlockList.blocks() = %v, want %v",
+				url, got, want)
+		}
+	}
+} // Test_tBlockList_blocks()
+
+func Test_newBlocker(t *testing.T) {
+	const (
+		fName = "./Crash_Test_Dummies.block"
+		lName = "./Crash_Test_Dummies.hosts"
+	)
+	rules := `
+*	list	Crash_Test_Dummies.hosts
+*	url	*://*/banner/*
+example.com	type	Media
+`
+	hosts := `# hosts file
+0.0.0.0	ads.example.net
+127.0.0.1	localhost
+||tracker.example.org^
+`
+	writeFile(fName, []byte(rules), nil)
+	writeFile(lName, []byte(hosts), nil)
+	defer func() {
+		_ = os.Remove(fName)
+		_ = os.Remove(lName)
+		SetBlockFile("")
+	}()
+	SetBlockFile(fName)
+
+	tests := []struct {
+		name  string
+		aPage string
+		aURL  string
+		aType network.ResourceType
+		want  bool
+	}{
+		{"1", "https://example.org/", "https://example.org/logo.png", network.ResourceTypeImage, false},
+		{"2", "https://example.org/", "https://ads.example.net/ad.js", network.ResourceTypeScript, true},
+		{"3", "https://example.org/", "https://cdn.ads.example.net/ad.js", network.ResourceTypeScript, true},
+		{"4", "https://example.org/", "https://example.net/ad.js", network.ResourceTypeScript, false},
+		{"5", "https://example.org/", "https://tracker.example.org/t.gif", network.ResourceTypeImage, true},
+		{"6", "https://example.org/", "https://example.org/banner/1.png", network.ResourceTypeImage, true},
+		{"7", "https://example.org/", "https://example.org/video.mp4", network.ResourceTypeMedia, false},
+		{"8", "https://www.example.com/", "https://example.org/video.mp4", network.ResourceTypeMedia, true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocker := newBlocker(tt.aPage)
+			if nil == blocker {
+				t.Fatalf("%q: newBlocker() = nil", tt.name)
+			}
+			if got := blocker.blocks(tt.aURL, tt.aType); got != tt.want {
+				t.Errorf("%q: blocks() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_newBlocker()
+
+func Test_newBlocker_concurrent(t *testing.T) {
+	const (
+		fName = "./Crash_Test_Dummies.concurrent.block"
+		lName = "./Crash_Test_Dummies.concurrent.hosts"
+	)
+	writeFile(fName, []byte("*\tlist\tCrash_Test_Dummies.concurrent.hosts\n"), nil)
+	writeFile(lName, []byte("0.0.0.0\tads.example.net\n"), nil)
+	defer func() {
+		_ = os.Remove(fName)
+		_ = os.Remove(lName)
+		SetBlockFile("")
+	}()
+	SetBlockFile(fName)
+
+	// Concurrent captures share the cached rules and filter lists
+	// while the rules file may be changed:
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if blocker := newBlocker("https://example.org/"); nil != blocker {
+					_ = blocker.blocks("https://ads.example.net/ad.js", network.ResourceTypeScript)
+				}
+			}
+		}()
+	}
+	for j := 0; j < 20; j++ {
+		SetBlockFile(fName)
+	}
+	wg.Wait()
+
+	blocker := newBlocker("https://example.org/")
+	if (nil == blocker) || !blocker.blocks("https://ads.example.net/ad.js", network.ResourceTypeScript) {
+		t.Error("newBlocker(): request not blocked")
+	}
+} // Test_newBlocker_concurrent()
+
+func Test_parseFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		aLine    string
+		wantHost string
+		match    string // URL the pattern should match
+	}{
+		{"1", "", "", ""},
+		{"2", "# comment", "", ""},
+		{"3", "! EasyList comment", "", ""},
+		{"4", "0.0.0.0 Ads.Example.com", "ads.example.com", ""},
+		{"5", "127.0.0.1 localhost", "", ""},
+		{"6", "ads.example.com", "ads.example.com", ""},
+		{"7", "||ads.example.com^", "ads.example.com", ""},
+		{"8", "##.ad-banner", "", ""},
+		{"9", "@@||example.com^", "", ""},
+		{"10", "||example.com^$third-party", "", ""},
+		{"11", "||example.com/ads/", "", "https://www.example.com/ads/1.png"},
+		{"12", "/banner/*/img^", "", "http://example.org/banner/foo/img?x=1"},
+		{"13", "|https://ads.", "", "https://ads.example.net/"},
+		{"14", "*/ads/*", "", "https://example.net/ads/1.png"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, re := parseFilter(tt.aLine)
+			if host != tt.wantHost {
+				t.Errorf("%q: parseFilter() host = %q, want %q",
+					tt.name, host, tt.wantHost)
+			}
+			if 0 == len(tt.match) {
+				if nil != re {
+					t.Errorf("%q: parseFilter() pattern = %v, want nil",
+						tt.name, re)
+				}
+				return
+			}
+			if (nil == re) || !re.MatchString(tt.match) {
+				t.Errorf("%q: parseFilter() pattern = %v, doesn't match %q",
+					tt.name, re, tt.match)
+			}
+		})
+	}
+} // Test_parseFilter()
+
+func Test_wildcardRE(t *testing.T) {
+	tests := []struct {
+		name     string
+		aPattern string
+		aURL     string
+		want     bool
+	}{
+		{"1", "*", "https://example.com/", true},
+		{"2", "*://*/ads/*", "https://example.com/ads/1.png", true},
+		{"3", "*://*/ads/*", "https://example.com/news/1.png", false},
+		{"4", "*.woff?", "https://example.com/font.woff2", true},
+		{"5", "*.woff?", "https://example.com/font.woff", false},
+		{"6", "https://example.com/a+b", "https://example.com/a+b", true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wildcardRE(tt.aPattern).MatchString(tt.aURL); got != tt.want {
+				t.Errorf("%q: wildcardRE() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_wildcardRE()
+
+/* _EoF_ */
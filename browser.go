@@ -0,0 +1,349 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Use a fresh throwaway profile for each capture (default).
+	ProfileEphemeral TProfileMode = iota
+
+	// Use a single persistent profile for all captures.
+	ProfileShared
+
+	// Use a separate persistent profile for each host.
+	ProfilePerHost
+)
+
+type (
+	// TProfileMode determines which browser profile (user-data
+	// directory) is used for a capture.
+	TProfileMode int
+)
+
+// `String()` returns the name of the profile mode.
+//
+// Returns:
+//   - `string`: The profile mode's name.
+func (pm TProfileMode) String() string {
+	switch pm {
+	case ProfileShared:
+		return "shared"
+	case ProfilePerHost:
+		return "per-host"
+	default:
+		return "ephemeral"
+	}
+} // String()
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `browserContext()` returns a new browser context for processing
+// the web page `aURL`.
+//
+// Depending on the [BrowserReuse] setting the context either uses
+// the supervised browser shared by all captures or a browser of its
+// own; depending on the [RemoteURL] setting that browser either is
+// an already running one or launched locally.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `context.Context`: The browser context.
+//   - `context.CancelFunc`: The function to release the browser context.
+//   - `error`: A possible error starting the browser.
+func browserContext(aContext context.Context, aURL string) (context.Context, context.CancelFunc, error) {
+	if ssOptions.BrowserReuse {
+		return ssSupervisor.tab(aContext, aURL)
+	}
+
+	var (
+		allocCtx    context.Context
+		allocCancel context.CancelFunc
+	)
+	ctxOptions := []chromedp.ContextOption{
+		chromedp.WithLogf(log.Printf),
+	}
+
+	if 0 < len(ssOptions.RemoteURL) {
+		allocCtx, allocCancel = chromedp.NewRemoteAllocator(aContext, ssOptions.RemoteURL)
+		ctxOptions = append(ctxOptions, tabOptions(aURL)...)
+	} else {
+		allocCtx, allocCancel = chromedp.NewExecAllocator(aContext, execOptions(aURL)...)
+	}
+	ctx, cancel := chromedp.NewContext(allocCtx, ctxOptions...)
+
+	return ctx, func() {
+		cancel()
+		allocCancel()
+	}, nil
+} // browserContext()
+
+// `chromeFlags()` returns the [ChromeFlags] setting as a list of
+// allocator options.
+//
+// Returns:
+//   - `[]chromedp.ExecAllocatorOption`: The additional command-line flags.
+func chromeFlags() (rList []chromedp.ExecAllocatorOption) {
+	for _, arg := range strings.Fields(ssOptions.ChromeFlags) {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if 0 == len(name) {
+			continue
+		}
+		if hasValue {
+			rList = append(rList, chromedp.Flag(name, value))
+		} else {
+			rList = append(rList, chromedp.Flag(name, true))
+		}
+	}
+
+	return
+} // chromeFlags()
+
+// `execOptions()` returns the options for launching a local browser
+// to process the web page `aURL`.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `[]chromedp.ExecAllocatorOption`: The options to use.
+func execOptions(aURL string) []chromedp.ExecAllocatorOption {
+	options := append([]chromedp.ExecAllocatorOption{},
+		chromedp.DefaultExecAllocatorOptions[:]...)
+	if !ssOptions.Headless {
+		options = append(options, chromedp.Flag("headless", false))
+	}
+	if 0 < len(ssOptions.ExecPath) {
+		options = append(options, chromedp.ExecPath(ssOptions.ExecPath))
+	}
+	if dir := profileDir(aURL); 0 < len(dir) {
+		options = append(options, chromedp.UserDataDir(dir))
+	}
+	if proxy := proxyServer(aURL); 0 < len(proxy) {
+		options = append(options, chromedp.ProxyServer(proxy))
+		if list := bypassList(); 0 < len(list) {
+			options = append(options, chromedp.Flag("proxy-bypass-list", list))
+		}
+	}
+
+	// The user's flags come last to allow overriding the defaults:
+	return append(options, chromeFlags()...)
+} // execOptions()
+
+// `profileDir()` returns the user-data directory to use for the web
+// page `aURL` according to the [ProfileMode] setting.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `string`: The directory or an empty string for an ephemeral profile.
+func profileDir(aURL string) string {
+	if 0 == len(ssOptions.ProfileDir) {
+		return ""
+	}
+
+	switch ssOptions.ProfileMode {
+	case ProfileShared:
+		return ssOptions.ProfileDir
+
+	case ProfilePerHost:
+		if host := sanitise(hostOf(aURL)); 0 < len(host) {
+			return filepath.Join(ssOptions.ProfileDir, host)
+		}
+	}
+
+	return ""
+} // profileDir()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `ChromeFlags()` returns the additional command-line flags used
+// when launching a local browser.
+//
+// Returns:
+//   - `string`: The space separated list of flags.
+func ChromeFlags() string {
+	return ssOptions.ChromeFlags
+} // ChromeFlags()
+
+// `SetChromeFlags()` configures additional command-line flags to use
+// when launching a local browser, e.g.
+// `--no-sandbox --disable-gpu --window-size=1280,1024`.
+//
+// The flags are passed after the default flags and may thus override
+// them. They're ignored if a [RemoteURL] is configured.
+//
+// Parameters:
+//   - `aFlags`: The space separated list of flags.
+func SetChromeFlags(aFlags string) {
+	ssOptions.ChromeFlags = strings.Join(strings.Fields(aFlags), " ")
+} // SetChromeFlags()
+
+// `ExecPath()` returns the path of the local browser executable.
+//
+// Returns:
+//   - `string`: The path of the browser executable.
+func ExecPath() string {
+	return ssOptions.ExecPath
+} // ExecPath()
+
+// `SetExecPath()` configures the path of the local browser executable.
+//
+// An empty value lets the package look for the usual Chrome/Chromium
+// executables. The setting is ignored if a [RemoteURL] is configured.
+//
+// Parameters:
+//   - `aPath`: The path of the browser executable.
+func SetExecPath(aPath string) {
+	ssOptions.ExecPath = strings.TrimSpace(aPath)
+} // SetExecPath()
+
+// `Headless()` returns whether a local browser is launched without
+// a visible window.
+//
+// Returns:
+//   - `bool`: Whether to run the local browser headless.
+func Headless() bool {
+	return ssOptions.Headless
+} // Headless()
+
+// `SetHeadless()` configures whether a local browser is launched
+// without a visible window (the default).
+//
+// The setting is ignored if a [RemoteURL] is configured.
+//
+// Parameters:
+//   - `doHide`: Whether to run the local browser headless.
+func SetHeadless(doHide bool) {
+	ssOptions.Headless = doHide
+} // SetHeadless()
+
+// `ProfileDir()` returns the directory of the persistent browser
+// profile(s).
+//
+// Returns:
+//   - `string`: The directory of the browser profile(s).
+func ProfileDir() string {
+	return ssOptions.ProfileDir
+} // ProfileDir()
+
+// `SetProfileDir()` configures the directory of the persistent browser
+// profile(s), i.e. Chrome's user-data directory.
+//
+// With a persistent profile logins, `localStorage`, and consent
+// choices survive across captures and program runs.
+// Whether that directory is actually used depends on the
+// [ProfileMode] setting.
+// The directory is created if it doesn't exist; an empty or invalid
+// value disables persistent profiles.
+//
+// NOTE: Chrome locks its user-data directory, hence only one browser
+// at a time can use a given profile.
+//
+// Parameters:
+//   - `aDirectory`: The directory of the browser profile(s).
+func SetProfileDir(aDirectory string) {
+	ssOptions.ProfileDir = ""
+	if aDirectory = strings.TrimSpace(aDirectory); 0 == len(aDirectory) {
+		return
+	}
+
+	dir, err := filepath.Abs(aDirectory)
+	if nil != err {
+		return
+	}
+	if err = os.MkdirAll(dir, 0750); nil != err {
+		log.Println(ssLibName, err)
+		return
+	}
+	ssOptions.ProfileDir = dir
+} // SetProfileDir()
+
+// `ProfileMode()` returns which browser profile is used for a capture.
+//
+// Returns:
+//   - `TProfileMode`: The current profile mode.
+func ProfileMode() TProfileMode {
+	return ssOptions.ProfileMode
+} // ProfileMode()
+
+// `SetProfileMode()` configures which browser profile is used for a
+// capture:
+//
+//   - [ProfileEphemeral]: a fresh throwaway profile (default),
+//   - [ProfileShared]: a single persistent profile in [ProfileDir],
+//   - [ProfilePerHost]: a persistent profile for each host in a
+//     subdirectory of [ProfileDir].
+//
+// Without a [ProfileDir] all captures use an ephemeral profile.
+//
+// Parameters:
+//   - `aMode`: The profile mode to use.
+func SetProfileMode(aMode TProfileMode) {
+	if (ProfileEphemeral <= aMode) && (ProfilePerHost >= aMode) {
+		ssOptions.ProfileMode = aMode
+	} else {
+		ssOptions.ProfileMode = ProfileEphemeral
+	}
+} // SetProfileMode()
+
+// `RemoteURL()` returns the DevTools endpoint of an already running
+// browser to use instead of launching a local one.
+//
+// Returns:
+//   - `string`: The browser's DevTools URL.
+func RemoteURL() string {
+	return ssOptions.RemoteURL
+} // RemoteURL()
+
+// `SetRemoteURL()` configures the DevTools endpoint of an already
+// running browser (e.g. a sandboxed headless Chrome started with
+// `--remote-debugging-port=9222`) to use instead of launching a local
+// one, like `ws://127.0.0.1:9222/` or `http://127.0.0.1:9222/`.
+//
+// Each capture uses a new browser context of that browser which is
+// disposed afterwards; with a persistent profile (see [ProfileMode])
+// the remote browser's default context is used instead.
+// [ExecPath], [ChromeFlags], and [Headless] are ignored in that case.
+// An empty or invalid value launches a local browser (the default).
+//
+// Parameters:
+//   - `aURL`: The browser's DevTools URL.
+func SetRemoteURL(aURL string) {
+	ssOptions.RemoteURL = ""
+	if aURL = strings.TrimSpace(aURL); 0 == len(aURL) {
+		return
+	}
+	URL, err := url.Parse(aURL)
+	if (nil != err) || (0 == len(URL.Host)) {
+		return
+	}
+	switch URL.Scheme {
+	case "http", "https", "ws", "wss":
+		ssOptions.RemoteURL = aURL
+	}
+} // SetRemoteURL()
+
+/* _EoF_ */
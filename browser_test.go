@@ -0,0 +1,76 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_profileDir(t *testing.T) {
+	dir := filepath.Join(os.TempDir(), "Crash_Test_Dummies.profiles")
+	defer func(aMode TProfileMode) {
+		_ = os.RemoveAll(dir)
+		SetProfileDir("")
+		SetProfileMode(aMode)
+	}(ProfileMode())
+
+	tests := []struct {
+		name  string
+		aDir  string
+		aMode TProfileMode
+		aURL  string
+		want  string
+	}{
+		{"1", "", ProfileShared, "https://example.com/", ""},
+		{"2", dir, ProfileEphemeral, "https://example.com/", ""},
+		{"3", dir, ProfileShared, "https://example.com/", dir},
+		{"4", dir, ProfilePerHost, "https://www.Example.com/page", filepath.Join(dir, "wwwexamplecom")},
+		{"5", dir, ProfilePerHost, "file:///tmp/page.html", ""},
+		{"6", dir, TProfileMode(99), "https://example.com/", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetProfileDir(tt.aDir)
+			SetProfileMode(tt.aMode)
+			if got := profileDir(tt.aURL); got != tt.want {
+				t.Errorf("%q: profileDir() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_profileDir()
+
+func TestSetRemoteURL(t *testing.T) {
+	defer SetRemoteURL("")
+
+	tests := []struct {
+		name string
+		aURL string
+		want string
+	}{
+		{"1", "", ""},
+		{"2", " ws://127.0.0.1:9222/ ", "ws://127.0.0.1:9222/"},
+		{"3", "http://chrome:9222", "http://chrome:9222"},
+		{"4", "ftp://chrome:9222/", ""},
+		{"5", "127.0.0.1:9222", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRemoteURL(tt.aURL)
+			if got := RemoteURL(); got != tt.want {
+				t.Errorf("%q: SetRemoteURL() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // TestSetRemoteURL()
+
+/* _EoF_ */
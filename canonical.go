@@ -0,0 +1,156 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// The tracking parameters removed from URLs by default:
+	defaultCanonicalParams = `fbclid,gclid,utm_*`
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `canonical()` returns the canonical form of `aURL` which is used to
+// look up (and name) the cached image of a web page.
+//
+// The canonical form has a lowercase scheme and host (see
+// `normaliseHost()`), neither the scheme's default port nor a fragment,
+// and its query parameters sorted with the tracking parameters (see
+// [SetCanonicalParams]) removed. If the [CanonicalScheme] option is set
+// `http` URLs are turned into `https` ones.
+// URLs without a host (like `file:///tmp/index.html`) are returned
+// unchanged.
+//
+// Parameters:
+//   - `aURL`: The URL to canonicalise.
+//
+// Returns:
+//   - `string`: The canonical form of `aURL`.
+func canonical(aURL string) string {
+	URL, err := url.Parse(aURL)
+	if (nil != err) || (0 == len(URL.Host)) {
+		return aURL
+	}
+
+	URL.Scheme = strings.ToLower(URL.Scheme)
+	host := normaliseHost(URL.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 address
+	}
+	if port := URL.Port(); (0 < len(port)) && (defaultPort(URL.Scheme) != port) {
+		host += ":" + port
+	}
+	URL.Host = host
+	if ssOptions.CanonicalScheme && ("http" == URL.Scheme) {
+		URL.Scheme = "https"
+	}
+	URL.Fragment, URL.RawFragment = "", ""
+
+	if 0 < len(URL.RawQuery) {
+		if query, err := url.ParseQuery(URL.RawQuery); nil == err {
+			for name := range query {
+				if trackingParam(name) {
+					query.Del(name)
+				}
+			}
+			// `Encode()` sorts the parameters by name:
+			URL.RawQuery = query.Encode()
+		}
+	}
+	URL.ForceQuery = false
+
+	return URL.String()
+} // canonical()
+
+// `trackingParam()` returns whether the query parameter `aName` is
+// one of the [CanonicalParams] to remove.
+//
+// Parameters:
+//   - `aName`: The name of the query parameter.
+//
+// Returns:
+//   - `bool`: Whether to remove the parameter.
+func trackingParam(aName string) bool {
+	if 0 == len(ssOptions.CanonicalParams) {
+		return false
+	}
+	aName = strings.ToLower(aName)
+	for _, pattern := range strings.Split(ssOptions.CanonicalParams, ",") {
+		if ok, _ := path.Match(pattern, aName); ok {
+			return true
+		}
+	}
+
+	return false
+} // trackingParam()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `CanonicalParams()` returns the query parameters removed from a URL
+// before looking up its cached image.
+//
+// Returns:
+//   - `string`: The comma separated list of parameter names.
+func CanonicalParams() string {
+	return ssOptions.CanonicalParams
+} // CanonicalParams()
+
+// `SetCanonicalParams()` configures the query parameters (like the
+// `utm_*`, `fbclid`, and `gclid` tracking parameters of the default)
+// removed from a URL before looking up its cached image, so e.g.
+// `https://example.com/a?utm_source=x` and `https://example.com/a`
+// share the same image file.
+//
+// The names are matched case-insensitively and may contain the `*`
+// and `?` wildcards. An empty list keeps all parameters.
+//
+// NOTE: The pages are still retrieved using the URL as given.
+//
+// Parameters:
+//   - `aParams`: The comma separated list of parameter names.
+func SetCanonicalParams(aParams string) {
+	var list []string
+	for _, param := range strings.Split(strings.ToLower(aParams), ",") {
+		if param = strings.TrimSpace(param); 0 == len(param) {
+			continue
+		}
+		if _, err := path.Match(param, ""); nil == err {
+			list = append(list, param)
+		}
+	}
+	ssOptions.CanonicalParams = strings.Join(list, ",")
+} // SetCanonicalParams()
+
+// `CanonicalScheme()` returns whether `http` and `https` URLs share
+// the same cached image.
+//
+// Returns:
+//   - `bool`: Whether to ignore the URL's scheme.
+func CanonicalScheme() bool {
+	return ssOptions.CanonicalScheme
+} // CanonicalScheme()
+
+// `SetCanonicalScheme()` determines whether `http` and `https` URLs
+// of the same page share the same cached image; defaults to `false`.
+//
+// Parameters:
+//   - `doIgnore`: Whether to treat `http` and `https` as the same.
+func SetCanonicalScheme(doIgnore bool) {
+	ssOptions.CanonicalScheme = doIgnore
+} // SetCanonicalScheme()
+
+/* _EoF_ */
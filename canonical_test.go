@@ -0,0 +1,76 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"testing"
+)
+
+func Test_canonical(t *testing.T) {
+	defer func(aParams string, aScheme bool) {
+		ssOptions.CanonicalParams, ssOptions.CanonicalScheme = aParams, aScheme
+	}(ssOptions.CanonicalParams, ssOptions.CanonicalScheme)
+	SetCanonicalParams(defaultCanonicalParams)
+
+	tests := []struct {
+		name    string
+		aURL    string
+		aScheme bool
+		want    string
+	}{
+		{"1", "", false, ""},
+		{"2", "file:///tmp/Index.html#top", false, "file:///tmp/Index.html#top"},
+		{"3", "HTTP://Example.COM:80/a?utm_source=x#top", false, "http://example.com/a"},
+		{"4", "https://example.com/a", false, "https://example.com/a"},
+		{"5", "http://Example.com/a?utm_source=x#top", true, "https://example.com/a"},
+		{"6", "https://example.com:8443/a?b=2&a=1", false, "https://example.com:8443/a?a=1&b=2"},
+		{"7", "https://example.com/?FBCLID=1&q=x&gclid=2&UTM_Medium=3", false, "https://example.com/?q=x"},
+		{"8", "https://example.com/?", false, "https://example.com/"},
+		{"9", "https://Bücher.DE./Straße", false, "https://xn--bcher-kva.de/Stra%C3%9Fe"},
+		{"10", "http://[0:0::1]:80/", false, "http://[::1]/"},
+		{"11", "https://example.com/a?x=%zz&utm_source=1", false, "https://example.com/a?x=%zz&utm_source=1"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ssOptions.CanonicalScheme = tt.aScheme
+			if got := canonical(tt.aURL); got != tt.want {
+				t.Errorf("%q: canonical() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_canonical()
+
+func TestSetCanonicalParams(t *testing.T) {
+	defer func(aParams string) {
+		ssOptions.CanonicalParams = aParams
+	}(ssOptions.CanonicalParams)
+
+	tests := []struct {
+		name    string
+		aParams string
+		want    string
+	}{
+		{"1", "", ""},
+		{"2", " , ", ""},
+		{"3", "UTM_*, fbclid ,,gclid", "utm_*,fbclid,gclid"},
+		{"4", "ref,[", "ref"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCanonicalParams(tt.aParams)
+			if got := CanonicalParams(); got != tt.want {
+				t.Errorf("%q: SetCanonicalParams() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // TestSetCanonicalParams()
+
+/* _EoF_ */
@@ -0,0 +1,465 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// The preview image on top, the texts below (default).
+	CardLayoutLarge TCardLayout = iota
+
+	// The preview image on the left, the texts on the right.
+	CardLayoutSmall
+)
+
+const (
+	// Suffix appended to the sanitised URL to name a card image:
+	cardSuffix = `_card`
+
+	defaultCardHeight = 630
+
+	defaultCardWidth = 1200
+)
+
+type (
+	// TCardLayout determines the arrangement of a link preview card.
+	TCardLayout int
+
+	// TCardOptions bundles the configuration of the link preview
+	// cards generated by [CreateCard].
+	TCardOptions struct {
+		// The card's background colour.
+		Background color.Color
+
+		// Colour of the page's description text.
+		Description color.Color
+
+		// Colour of the page's domain text.
+		Domain color.Color
+
+		// Height of the card image (in pixels).
+		Height int
+
+		// The arrangement of image and texts.
+		Layout TCardLayout
+
+		// Colour of the page's title text.
+		Title color.Color
+
+		// Width of the card image (in pixels).
+		Width int
+	}
+)
+
+var (
+	// The currently used card options:
+	ssCardOptions = TCardOptions{
+		Background:  color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Description: color.RGBA{0x53, 0x64, 0x71, 0xff},
+		Domain:      color.RGBA{0x53, 0x64, 0x71, 0xff},
+		Height:      defaultCardHeight,
+		Layout:      CardLayoutLarge,
+		Title:       color.RGBA{0x0f, 0x14, 0x19, 0xff},
+		Width:       defaultCardWidth,
+	}
+
+	// The bundled fonts used to write the card's texts:
+	ssFontBold    = parseFont("gobold", gobold.TTF)
+	ssFontRegular = parseFont("goregular", goregular.TTF)
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `composeCard()` renders a link preview card of `aImage` and `aMeta`.
+//
+// Parameters:
+//   - `aImage`: The (possibly `nil`) preview image to use.
+//   - `aIcon`: The (possibly `nil`) favicon to use.
+//   - `aMeta`: The page's metadata.
+//   - `aDomain`: The page's domain to show.
+//
+// Returns:
+//   - `image.Image`: The rendered card.
+func composeCard(aImage, aIcon image.Image, aMeta *tPageMeta, aDomain string) image.Image {
+	opts := ssCardOptions
+	card := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	draw.Draw(card, card.Rect, image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	var imgRect, textRect image.Rectangle
+	switch opts.Layout {
+	case CardLayoutSmall:
+		side := min(opts.Height, opts.Width>>1)
+		imgRect = image.Rect(0, 0, side, opts.Height)
+		textRect = image.Rect(side, 0, opts.Width, opts.Height)
+
+	default:
+		split := opts.Height * 62 / 100
+		imgRect = image.Rect(0, 0, opts.Width, split)
+		textRect = image.Rect(0, split, opts.Width, opts.Height)
+	}
+	if nil == aImage {
+		// Use the whole card for the texts.
+		textRect = card.Rect
+	} else {
+		drawCover(card, imgRect, aImage)
+	}
+
+	padding := max(textRect.Dy()/12, 8)
+	textRect = textRect.Inset(padding)
+	lineSize := float64(textRect.Dy()) / 6.5
+	if CardLayoutSmall == opts.Layout {
+		lineSize = float64(textRect.Dy()) / 8
+	}
+	y := textRect.Min.Y
+
+	// The domain line with the page's favicon:
+	face := newFace(ssFontRegular, lineSize*0.7)
+	x := textRect.Min.X
+	if nil != aIcon {
+		iconSize := face.Metrics().Height.Ceil()
+		iconRect := image.Rect(x, y, x+iconSize, y+iconSize)
+		draw.BiLinear.Scale(card, iconRect, aIcon, aIcon.Bounds(), draw.Over, nil)
+		x += iconSize + (iconSize >> 1)
+	}
+	y = drawText(card, face, opts.Domain, aDomain,
+		image.Rect(x, y, textRect.Max.X, textRect.Max.Y), 1)
+	y += padding >> 1
+
+	// The page's title and description:
+	face = newFace(ssFontBold, lineSize)
+	y = drawText(card, face, opts.Title, aMeta.Title,
+		image.Rect(textRect.Min.X, y, textRect.Max.X, textRect.Max.Y), 2)
+	y += padding >> 2
+
+	face = newFace(ssFontRegular, lineSize*0.75)
+	drawText(card, face, opts.Description, aMeta.Description,
+		image.Rect(textRect.Min.X, y, textRect.Max.X, textRect.Max.Y), 3)
+
+	return card
+} // composeCard()
+
+// `drawCover()` scales `aImage` to cover the whole `aRect` of `aCard`
+// cutting off what doesn't fit.
+//
+// Parameters:
+//   - `aCard`: The image to draw on.
+//   - `aRect`: The area of `aCard` to cover.
+//   - `aImage`: The image to draw.
+func drawCover(aCard draw.Image, aRect image.Rectangle, aImage image.Image) {
+	src := aImage.Bounds()
+	if src.Empty() || aRect.Empty() {
+		return
+	}
+
+	// Compare the aspect ratios (`src.Dx/src.Dy` vs. `aRect.Dx/aRect.Dy`):
+	if src.Dx()*aRect.Dy() > aRect.Dx()*src.Dy() {
+		// The source is wider: cut off left and right.
+		w := src.Dy() * aRect.Dx() / aRect.Dy()
+		src.Min.X += (src.Dx() - w) >> 1
+		src.Max.X = src.Min.X + w
+	} else {
+		// The source is higher: cut off the bottom.
+		src.Max.Y = src.Min.Y + src.Dx()*aRect.Dy()/aRect.Dx()
+	}
+
+	draw.BiLinear.Scale(aCard, aRect, aImage, src, draw.Over, nil)
+} // drawCover()
+
+// `drawText()` writes `aText` into `aRect` of `aCard` wrapping it
+// at word boundaries.
+//
+// Parameters:
+//   - `aCard`: The image to draw on.
+//   - `aFace`: The font face to use.
+//   - `aColour`: The text's colour.
+//   - `aText`: The text to write.
+//   - `aRect`: The area available for the text.
+//   - `aMaxLines`: The max. number of lines to write.
+//
+// Returns:
+//   - `int`: The vertical position below the written text.
+func drawText(aCard draw.Image, aFace font.Face, aColour color.Color, aText string, aRect image.Rectangle, aMaxLines int) int {
+	y := aRect.Min.Y
+	if aText = strings.TrimSpace(aText); 0 == len(aText) {
+		return y
+	}
+
+	drawer := &font.Drawer{
+		Dst:  aCard,
+		Src:  image.NewUniform(aColour),
+		Face: aFace,
+	}
+	metrics := aFace.Metrics()
+	lines := wrapText(drawer, aText, fixed.I(aRect.Dx()), aMaxLines)
+
+	for _, line := range lines {
+		if y+metrics.Height.Ceil() > aRect.Max.Y {
+			break
+		}
+		drawer.Dot = fixed.Point26_6{
+			X: fixed.I(aRect.Min.X),
+			Y: fixed.I(y) + metrics.Ascent,
+		}
+		drawer.DrawString(line)
+		y += metrics.Height.Ceil()
+	}
+
+	return y
+} // drawText()
+
+// `newFace()` returns a font face of `aFont` with the given size.
+//
+// If `aFont` is `nil` or the face can't be created a basic fixed
+// size face is returned instead.
+//
+// Parameters:
+//   - `aFont`: The font to use.
+//   - `aSize`: The font's size (in pixels).
+//
+// Returns:
+//   - `font.Face`: The font face to draw with.
+func newFace(aFont *opentype.Font, aSize float64) font.Face {
+	if nil == aFont {
+		return basicfont.Face7x13
+	}
+	face, err := opentype.NewFace(aFont, &opentype.FaceOptions{
+		Size:    aSize,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if nil != err {
+		log.Println(ssLibName, err)
+		return basicfont.Face7x13
+	}
+
+	return face
+} // newFace()
+
+// `parseFont()` parses the bundled font `aData`.
+//
+// Parameters:
+//   - `aName`: The font's name (for logging).
+//   - `aData`: The font's TrueType data.
+//
+// Returns:
+//   - `*opentype.Font`: The parsed font or `nil` in case of errors.
+func parseFont(aName string, aData []byte) *opentype.Font {
+	result, err := opentype.Parse(aData)
+	if nil != err {
+		log.Printf("%s: can't parse font '%s': %v", ssLibName, aName, err)
+		return nil
+	}
+
+	return result
+} // parseFont()
+
+// `readImage()` reads and decodes the image file `aFilename`.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the image.
+//
+// Returns:
+//   - `image.Image`: The decoded image or `nil` in case of errors.
+func readImage(aFilename string) image.Image {
+	file, err := os.Open(aFilename) // #nosec G304
+	if nil != err {
+		return nil
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if nil != err {
+		return nil
+	}
+
+	return img
+} // readImage()
+
+// `wrapText()` splits `aText` into lines not wider than `aWidth`.
+//
+// If the text needs more than `aMaxLines` lines the last line is
+// shortened and an ellipsis appended.
+//
+// Parameters:
+//   - `aDrawer`: The drawer used to measure the text.
+//   - `aText`: The text to split.
+//   - `aWidth`: The max. width of a line.
+//   - `aMaxLines`: The max. number of lines to return.
+//
+// Returns:
+//   - `[]string`: The lines to draw.
+func wrapText(aDrawer *font.Drawer, aText string, aWidth fixed.Int26_6, aMaxLines int) []string {
+	var (
+		line   string
+		result []string
+	)
+
+	for _, word := range strings.Fields(aText) {
+		candidate := word
+		if 0 < len(line) {
+			candidate = line + " " + word
+		}
+		if (0 == len(line)) || (aDrawer.MeasureString(candidate) <= aWidth) {
+			line = candidate
+			continue
+		}
+		result = append(result, line)
+		line = word
+	}
+	if 0 < len(line) {
+		result = append(result, line)
+	}
+
+	if len(result) > aMaxLines {
+		result = result[:aMaxLines]
+		// Shorten the last line but keep at least the ellipsis:
+		runes := []rune(result[aMaxLines-1] + "…")
+		for (1 < len(runes)) && (aDrawer.MeasureString(string(runes)) > aWidth) {
+			runes = append(runes[:len(runes)-2], '…')
+		}
+		result[aMaxLines-1] = string(runes)
+	}
+
+	return result
+} // wrapText()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `CardOptions()` returns the current configuration of the link
+// preview cards generated by [CreateCard].
+//
+// Returns:
+//   - `TCardOptions`: The current card options.
+func CardOptions() TCardOptions {
+	return ssCardOptions
+} // CardOptions()
+
+// `SetCardOptions()` changes the configuration of the link preview
+// cards generated by [CreateCard].
+//
+// Invalid (i.e. non-positive) sizes are reset to `1200x630` pixels,
+// missing colours keep their current value.
+//
+// Parameters:
+//   - `aOptions`: The new card options.
+func SetCardOptions(aOptions TCardOptions) {
+	if 0 >= aOptions.Height {
+		aOptions.Height = defaultCardHeight
+	}
+	if 0 >= aOptions.Width {
+		aOptions.Width = defaultCardWidth
+	}
+	if (CardLayoutLarge > aOptions.Layout) || (CardLayoutSmall < aOptions.Layout) {
+		aOptions.Layout = CardLayoutLarge
+	}
+	if nil == aOptions.Background {
+		aOptions.Background = ssCardOptions.Background
+	}
+	if nil == aOptions.Description {
+		aOptions.Description = ssCardOptions.Description
+	}
+	if nil == aOptions.Domain {
+		aOptions.Domain = ssCardOptions.Domain
+	}
+	if nil == aOptions.Title {
+		aOptions.Title = ssCardOptions.Title
+	}
+
+	ssCardOptions = aOptions
+} // SetCardOptions()
+
+// `CreateCard()` generates a link preview card of `aURL` and stores it
+// in [ImageDir], returning the file name of the saved card image or an
+// error in case of problems.
+//
+// The card combines the page's preview image (see [Capture]) with the
+// page's title, description, domain and favicon, using the layout,
+// size and colours configured by [SetCardOptions].
+// If the preview image can't be captured the error of [Capture] is
+// returned.
+// The card is saved in the configured [ImageType] under the name of
+// the preview image with an additional `_card` suffix.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `string`: The file name of the saved card image.
+//   - `error`: A possible error during creation of the card image.
+func CreateCard(aURL string) (string, error) {
+	result := sanitise(canonical(aURL)) + cardSuffix + `.` + ImageType()
+	fName := filepath.Join(ssOptions.ImageDir, result)
+	if exists(ssOptions, fName) {
+		return result, nil
+	}
+
+	pURL, err := url.Parse(aURL)
+	if nil != err {
+		return "", err
+	}
+
+	capture, err := Capture(aURL)
+	if nil != err {
+		return "", err
+	}
+	// An unreadable preview image (like a downloaded SVG) is not
+	// fatal: the card then consists of the texts only.
+	img := readImage(filepath.Join(ssOptions.ImageDir, capture.Filename))
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(ssOptions.MaxProcessTime)*time.Second)
+	defer cancel()
+
+	meta, err := fetchMetadata(ctx, ssOptions, aURL)
+	if nil != err {
+		if nil == img {
+			return "", err
+		}
+		meta = &tPageMeta{}
+	}
+	var icon image.Image
+	if 0 < len(meta.Icon) {
+		icon, _ = downloadImage(ctx, ssOptions, meta.Icon)
+	}
+
+	card := composeCard(img, icon, meta, strings.TrimPrefix(pURL.Hostname(), "www."))
+	data := encodeImage(ssOptions, card)
+	if 0 == len(data) {
+		return "", errors.New(ssLibName + ": can't encode card for '" + aURL + "'")
+	}
+	if err = writeFile(fName, data, nil); nil != err {
+		return "", err
+	}
+
+	return result, nil
+} // CreateCard()
+
+/* _EoF_ */
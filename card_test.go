@@ -0,0 +1,133 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+func Test_composeCard(t *testing.T) {
+	defer SetCardOptions(CardOptions())
+
+	img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	meta := &tPageMeta{
+		Description: "A description",
+		Title:       "A Title",
+	}
+
+	tests := []struct {
+		name   string
+		layout TCardLayout
+		aImage image.Image
+		width  int
+		height int
+	}{
+		{"1", CardLayoutLarge, img, 1200, 630},
+		{"2", CardLayoutSmall, img, 800, 200},
+		{"3", CardLayoutLarge, nil, 600, 315},
+		{"4", CardLayoutLarge, img, 30, 630},
+		{"5", CardLayoutSmall, nil, 30, 30},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCardOptions(TCardOptions{
+				Height: tt.height,
+				Layout: tt.layout,
+				Width:  tt.width,
+			})
+			got := composeCard(tt.aImage, nil, meta, "example.com")
+			if size := got.Bounds().Size(); (size.X != tt.width) || (size.Y != tt.height) {
+				t.Errorf("%q: composeCard() size = %v, want %dx%d",
+					tt.name, size, tt.width, tt.height)
+			}
+		})
+	}
+} // Test_composeCard()
+
+func TestSetCardOptions(t *testing.T) {
+	defer SetCardOptions(CardOptions())
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	o1 := TCardOptions{}
+	w1 := CardOptions()
+	//
+	o2 := TCardOptions{Height: 200, Layout: CardLayoutSmall, Title: red, Width: 800}
+	w2 := CardOptions()
+	w2.Height, w2.Layout, w2.Title, w2.Width = 200, CardLayoutSmall, red, 800
+	//
+	o3 := TCardOptions{Height: -1, Layout: 42, Width: -1}
+	w3 := w2
+	w3.Height, w3.Layout, w3.Width = defaultCardHeight, CardLayoutLarge, defaultCardWidth
+
+	tests := []struct {
+		name     string
+		aOptions TCardOptions
+		want     TCardOptions
+	}{
+		{"1", o1, w1},
+		{"2", o2, w2},
+		{"3", o3, w3},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCardOptions(tt.aOptions)
+			if got := CardOptions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q: SetCardOptions() = %v,\nwant %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // TestSetCardOptions()
+
+func Test_wrapText(t *testing.T) {
+	drawer := &font.Drawer{Face: newFace(ssFontRegular, 10)}
+	width := drawer.MeasureString("abcdefghij")
+
+	tests := []struct {
+		name      string
+		aText     string
+		aWidth    fixed.Int26_6
+		aMaxLines int
+		want      []string
+	}{
+		{"1", "", width, 2, nil},
+		{"2", "abc def", width, 2, []string{"abc def"}},
+		{"3", "abc def ghi", width, 2, []string{"abc def", "ghi"}},
+		{"4", "abc def ghi jkl mno", width, 2, []string{"abc def", "ghi jkl…"}},
+		{"5", "abcdefghijklmno", width, 1, []string{"abcdefghijklmno"}},
+		{"6", "aaa bbb ccc ddd", fixed.I(1), 2, []string{"aaa", "…"}},
+		{"7", "äöü ßäö üäö", fixed.I(1), 1, []string{"…"}},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapText(drawer, tt.aText, tt.aWidth, tt.aMaxLines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q: wrapText() = %q,\nwant %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_wrapText()
+
+func Test_newFace(t *testing.T) {
+	if face := newFace(nil, 10); nil == face {
+		t.Error("newFace(nil) = nil, want a fallback face")
+	}
+	if face := newFace(ssFontRegular, -1); nil == face {
+		t.Error("newFace(-1) = nil, want a fallback face")
+	}
+} // Test_newFace()
+
+/* _EoF_ */
@@ -0,0 +1,172 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+The consent rules file is a host rules file (see `hostrules.go`) with
+the actions
+
+	click SELECTOR
+	hide  SELECTOR
+
+`click` clicks the first element matching the given CSS selector
+(e.g. a consent manager's "reject" button), `hide` hides all elements
+matching the given CSS selector (e.g. the consent manager's dialog).
+`click` rules are applied only if JavaScript is active for the page.
+*/
+
+const (
+	// Rule keyword for CSS selectors of elements to click:
+	actionClick = `click`
+
+	// Filename of the list of cookie-consent rules:
+	defaultConsentRules = `consentrules.list`
+)
+
+var (
+	// The rules of the cookie-consent rules file:
+	ssConsent = tHostRules{
+		filename: setHosts4JS("./", defaultConsentRules),
+		kind:     RulesConsent,
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `clickScript()` returns a JavaScript expression clicking the first
+// element matching `aSelector`.
+//
+// The expression returns whether such an element was found.
+//
+// Parameters:
+//   - `aSelector`: The CSS selector of the element to click.
+//
+// Returns:
+//   - `string`: The JavaScript expression.
+func clickScript(aSelector string) string {
+	// JSON encoding results in a valid JavaScript string literal.
+	sel, _ := json.Marshal(aSelector)
+
+	return `(function(){var e=document.querySelector(` + string(sel) +
+		`);if(!e){return false}e.click();return true})()`
+} // clickScript()
+
+// `consentTasks()` returns the browser actions suppressing the
+// cookie-consent banners on the page of `aURL`.
+//
+// Since consent managers usually inject their banners after the page
+// is loaded, the actions are meant to run after waiting for the page
+// (see `waitTasks()`). The `hide` rules are always added to the page
+// while the rules which actually matched an element of the page are
+// reported in `aCapture`.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//   - `aEnableJS`: Whether JavaScript is active for the page.
+//   - `aCapture`: The capture result to receive the applied rules.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform after waiting for the page.
+func consentTasks(aURL string, aEnableJS bool, aCapture *TCaptureResult) chromedp.Tasks {
+	var clicks, hides []string
+	if aEnableJS {
+		clicks = ssConsent.args(aURL, actionClick)
+	}
+	if hides = ssConsent.args(aURL, actionHide); (0 == len(clicks)) && (0 == len(hides)) {
+		return nil
+	}
+
+	return chromedp.Tasks{
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			for _, sel := range clicks {
+				var clicked bool
+				if err := chromedp.Evaluate(clickScript(sel), &clicked).Do(aContext); (nil == err) && clicked {
+					aCapture.ConsentRules = append(aCapture.ConsentRules,
+						actionClick+" "+sel)
+				}
+			}
+
+			// Look which of the elements to hide are present to
+			// report them; this uses the DOM domain so it works
+			// without JS.
+			if root, err := dom.GetDocument().Do(aContext); nil == err {
+				for _, sel := range hides {
+					if ids, err := dom.QuerySelectorAll(root.NodeID, sel).Do(aContext); (nil == err) && (0 < len(ids)) {
+						aCapture.ConsentRules = append(aCapture.ConsentRules,
+							actionHide+" "+sel)
+					}
+				}
+			}
+
+			// Hide the elements anyway in case they show up later:
+			return styleTasks(hideRule(hides)).Do(aContext)
+		}),
+	}
+} // consentTasks()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `ConsentFile()` returns the name of the file containing the rules
+// to suppress cookie-consent banners.
+//
+// Returns:
+//   - `string`: The path/file name of the consent rules.
+func ConsentFile() string {
+	return ssOptions.ConsentFile
+} // ConsentFile()
+
+// `SetConsentFile()` configures the name of the file containing the
+// rules to suppress cookie-consent banners of well-known consent
+// managers.
+//
+// Each line of that file consists of a host/domain (or `*` for all
+// hosts), the keyword `hide` or `click`, and a CSS selector:
+//
+//	example.com  hide   .consent-layer
+//	*            click  #onetrust-reject-all-handler
+//	*            hide   #onetrust-consent-sdk
+//
+// `hide` hides all matching elements, `click` clicks the first
+// matching element (e.g. a "reject" button) if JavaScript is active
+// for the page. The rules are applied after waiting for the page
+// (see [SetWaitTime] and [SetWaitSelector]) since consent managers
+// usually show their banners only after the page is loaded.
+// The file is re-read according to the [ReadWaitTime] setting, and
+// the rules applied to a page are reported by [Capture] in the
+// `ConsentRules` field of its result.
+//
+// This package comes with such a file (`consentrules.list`) which is
+// used by default if found in the current directory.
+// An empty or invalid filename disables the feature.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the consent rules.
+func SetConsentFile(aFilename string) {
+	if aFilename = strings.TrimSpace(aFilename); 0 < len(aFilename) {
+		if _, ok := stat(aFilename); !ok {
+			// Maybe it's the directory containing the default file:
+			aFilename = setHosts4JS(aFilename, defaultConsentRules)
+		}
+	}
+	ssOptions.ConsentFile = ssConsent.setFile(aFilename)
+} // SetConsentFile()
+
+/* _EoF_ */
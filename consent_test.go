@@ -0,0 +1,63 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_clickScript(t *testing.T) {
+	tests := []struct {
+		name      string
+		aSelector string
+		want      string
+	}{
+		{"1", "#accept", `(function(){var e=document.querySelector("#accept");if(!e){return false}e.click();return true})()`},
+		{"2", `button[title="OK"]`, `(function(){var e=document.querySelector("button[title=\"OK\"]");if(!e){return false}e.click();return true})()`},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clickScript(tt.aSelector); got != tt.want {
+				t.Errorf("%q: clickScript() = %v,\nwant %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_clickScript()
+
+func TestSetConsentFile(t *testing.T) {
+	defer SetConsentFile(ConsentFile())
+
+	cwd, _ := os.Getwd()
+	w1 := filepath.Join(cwd, defaultConsentRules)
+
+	tests := []struct {
+		name      string
+		aFilename string
+		want      string
+	}{
+		{"1", "", ""},
+		{"2", "./", w1},
+		{"3", defaultConsentRules, w1},
+		{"4", "/dev/not/there", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetConsentFile(tt.aFilename)
+			if got := ConsentFile(); got != tt.want {
+				t.Errorf("%q: SetConsentFile() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // TestSetConsentFile()
+
+/* _EoF_ */
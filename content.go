@@ -0,0 +1,386 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	_ "golang.org/x/image/bmp"  // register BMP decoder
+	_ "golang.org/x/image/tiff" // register TIFF decoder
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Download an image and convert it to the configured [ImageType].
+	MimeConvert = `convert`
+
+	// Download an image and store it as is.
+	MimeDownload = `download`
+
+	// Refuse to process the resource.
+	MimeReject = `reject`
+
+	// Let the browser render the resource and take a screenshot.
+	MimeRender = `render`
+
+	// Number of bytes to retrieve for sniffing the content type:
+	probeSize = 512
+)
+
+var (
+	// The built-in MIME policy (see [SetMimePolicy]):
+	ssMimePolicy = map[string]string{
+		`*/*`:                   MimeRender,
+		`application/*`:         MimeReject,
+		`application/xhtml+xml`: MimeRender,
+		`application/xml`:       MimeRender,
+		`audio/*`:               MimeReject,
+		`font/*`:                MimeReject,
+		`image/*`:               MimeRender,
+		`image/bmp`:             MimeConvert,
+		`image/gif`:             MimeDownload,
+		`image/jpeg`:            MimeDownload,
+		`image/png`:             MimeDownload,
+		`image/svg+xml`:         MimeDownload,
+		`image/tiff`:            MimeConvert,
+		`image/webp`:            MimeConvert,
+		`model/*`:               MimeReject,
+		`text/*`:                MimeRender,
+		`text/calendar`:         MimeReject,
+		`text/csv`:              MimeReject,
+		`text/markdown`:         MimeReject,
+		`video/*`:               MimeReject,
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `contentType()` returns the MIME type of the resource addressed
+// by `aURL`.
+//
+// The first bytes of the resource are retrieved by a ranged GET
+// request (or read from the local file) to check the declared
+// content type (see `mimeType()`).
+// If the server can't be reached an empty string is returned so the
+// browser gets the chance to report the (possibly transient) error.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the resource to check.
+//
+// Returns:
+//   - `string`: The resource's MIME type or an empty string if unknown.
+func contentType(aContext context.Context, aOptions *TScreenshotParams, aURL string) string {
+	var (
+		declared string
+		head     []byte
+	)
+	ext := strings.ToLower(fileExt(aURL))
+
+	if fPath, ok := localFile(aURL); ok {
+		declared = mime.TypeByExtension(ext)
+		if file, err := os.Open(fPath); /* #nosec G304 */ nil == err {
+			head, _ = io.ReadAll(io.LimitReader(file, probeSize))
+			file.Close()
+		}
+	} else {
+		response, err := httpDo(aContext, aOptions, aURL,
+			map[string]string{"Range": fmt.Sprintf("bytes=0-%d", probeSize-1)})
+		if nil != err {
+			return ""
+		}
+		// An error page is rendered like any other page while
+		// an empty resource leaves just the filename extension:
+		if http.StatusRequestedRangeNotSatisfiable != response.StatusCode {
+			declared = response.Header.Get("Content-Type")
+			head, _ = io.ReadAll(io.LimitReader(response.Body, probeSize))
+		}
+		response.Body.Close()
+	}
+
+	return mimeType(declared, head, ext)
+} // contentType()
+
+// `convertImage()` retrieves the image addressed by `aURL` and returns
+// it adjusted to the configured size and encoded in the configured
+// [ImageType].
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the image to convert.
+//
+// Returns:
+//   - `[]byte`: The properly encoded image data.
+//   - `error`: A possible processing error.
+func convertImage(aContext context.Context, aOptions *TScreenshotParams, aURL string) ([]byte, error) {
+	fPath, ok := localFile(aURL)
+	if !ok {
+		img, err := downloadImage(aContext, aOptions, aURL)
+		if nil != err {
+			return nil, err
+		}
+
+		return encodeImage(aOptions, cropScale(aOptions, img)), nil
+	}
+
+	file, err := os.Open(fPath) // #nosec G304
+	if nil != err {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(io.LimitReader(file, maxImageSize))
+	if nil != err {
+		return nil, errors.New(ssLibName + ": can't decode image '" +
+			aURL + "': " + err.Error())
+	}
+
+	return encodeImage(aOptions, cropScale(aOptions, img)), nil
+} // convertImage()
+
+// `downloadExt()` returns the filename extension to use for a
+// downloaded resource of the MIME type `aType`.
+//
+// Parameters:
+//   - `aType`: The resource's MIME type.
+//   - `aExt`: The filename extension of the resource's URL.
+//
+// Returns:
+//   - `string`: The filename extension (including the dot).
+func downloadExt(aType, aExt string) string {
+	exts, _ := mime.ExtensionsByType(aType)
+	if (0 < len(aExt)) && slices.Contains(exts, aExt) {
+		return aExt
+	}
+
+	switch aType {
+	case `image/gif`:
+		return `.gif`
+	case `image/jpeg`:
+		return `.jpeg`
+	case `image/png`:
+		return `.png`
+	case `image/svg+xml`:
+		return `.svg`
+	}
+	if 0 < len(exts) {
+		return exts[0]
+	}
+
+	return aExt
+} // downloadExt()
+
+// `genericType()` returns whether `aType` doesn't tell much about
+// the actual content.
+//
+// Parameters:
+//   - `aType`: The MIME type to check.
+//
+// Returns:
+//   - `bool`: Whether the type is missing or generic.
+func genericType(aType string) bool {
+	switch aType {
+	case ``, `application/octet-stream`, `binary/octet-stream`, `text/plain`:
+		return true
+	}
+
+	return false
+} // genericType()
+
+// `mediaType()` returns the lowercase media type of the `Content-Type`
+// value `aValue` without any parameters.
+//
+// Parameters:
+//   - `aValue`: The `Content-Type` value.
+//
+// Returns:
+//   - `string`: The media type or an empty string if invalid.
+func mediaType(aValue string) string {
+	if 0 == len(strings.TrimSpace(aValue)) {
+		return ""
+	}
+	result, _, err := mime.ParseMediaType(aValue)
+	if (nil != err) || !strings.Contains(result, "/") {
+		return ""
+	}
+
+	return result
+} // mediaType()
+
+// `mimeAction()` returns how to process a resource of the MIME type
+// `aType` according to the [MimePolicy] and the built-in policy.
+//
+// Each policy is checked for the exact type, the type's wildcard
+// (like `image/*`), and `*/*`, in that order.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aType`: The MIME type to look up.
+//
+// Returns:
+//   - `string`: One of [MimeConvert], [MimeDownload], [MimeReject], or [MimeRender].
+func mimeAction(aOptions *TScreenshotParams, aType string) string {
+	keys := []string{aType}
+	if idx := strings.Index(aType, "/"); 0 < idx {
+		keys = append(keys, aType[:idx]+"/*")
+	}
+	keys = append(keys, "*/*")
+
+	for _, policy := range []map[string]string{parseMimePolicy(aOptions.MimePolicy), ssMimePolicy} {
+		for _, key := range keys {
+			if action, ok := policy[key]; ok {
+				return action
+			}
+		}
+	}
+
+	return MimeRender
+} // mimeAction()
+
+// `mimeType()` returns the MIME type of a resource.
+//
+// The declared content type is used unless it's missing or generic
+// (like `application/octet-stream` or `text/plain`); in that case the
+// type sniffed from `aHead` or – if that's generic as well – the type
+// derived from the filename extension `aExt` is used.
+//
+// Parameters:
+//   - `aDeclared`: The `Content-Type` reported by the server.
+//   - `aHead`: The first bytes of the resource.
+//   - `aExt`: The filename extension of the resource's URL.
+//
+// Returns:
+//   - `string`: The resource's MIME type or an empty string if unknown.
+func mimeType(aDeclared string, aHead []byte, aExt string) string {
+	result := mediaType(aDeclared)
+	if !genericType(result) {
+		return result
+	}
+	if 0 < len(aHead) {
+		if sniffed := mediaType(http.DetectContentType(aHead)); !genericType(sniffed) {
+			return sniffed
+		} else if 0 == len(result) {
+			result = sniffed
+		}
+	}
+	if 0 < len(aExt) {
+		if byExt := mediaType(mime.TypeByExtension(aExt)); 0 < len(byExt) {
+			return byExt
+		}
+	}
+
+	return result
+} // mimeType()
+
+// `normaliseMimePolicy()` returns the valid entries of `aPolicy`
+// in sorted order.
+//
+// Parameters:
+//   - `aPolicy`: The comma separated list of `type=action` entries.
+//
+// Returns:
+//   - `string`: The comma separated list of valid entries.
+func normaliseMimePolicy(aPolicy string) string {
+	var list []string
+	for mType, action := range parseMimePolicy(aPolicy) {
+		list = append(list, mType+"="+action)
+	}
+	slices.Sort(list)
+
+	return strings.Join(list, ",")
+} // normaliseMimePolicy()
+
+// `parseMimePolicy()` returns the policy described by `aPolicy`.
+//
+// Invalid entries are skipped.
+//
+// Parameters:
+//   - `aPolicy`: The comma separated list of `type=action` entries.
+//
+// Returns:
+//   - `map[string]string`: The actions by MIME type.
+func parseMimePolicy(aPolicy string) map[string]string {
+	if 0 == len(aPolicy) {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for _, entry := range strings.Split(strings.ToLower(aPolicy), ",") {
+		mType, action, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		mType, action = strings.TrimSpace(mType), strings.TrimSpace(action)
+		if idx := strings.Index(mType, "/"); (0 >= idx) || (len(mType)-1 == idx) {
+			continue
+		}
+		switch action {
+		case MimeConvert, MimeDownload, MimeReject, MimeRender:
+			result[mType] = action
+		}
+	}
+
+	return result
+} // parseMimePolicy()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `MimePolicy()` returns the entries overriding the built-in MIME
+// policy.
+//
+// Returns:
+//   - `string`: The comma separated list of `type=action` entries.
+func MimePolicy() string {
+	return ssOptions.MimePolicy
+} // MimePolicy()
+
+// `SetMimePolicy()` configures how the resources of certain MIME types
+// are processed, overriding the built-in policy.
+//
+// Before processing a URL its content type is determined by retrieving
+// its first bytes: the type declared by the server is used unless it's
+// missing or generic (like `application/octet-stream`); then the type
+// sniffed from the data or – as a last resort – the one derived from
+// the filename extension is used. The type is then looked up in the
+// policy, mapping it to one of these actions:
+//
+//   - [MimeConvert]: download the image and convert it to the configured [ImageType],
+//   - [MimeDownload]: download the image and store it as is,
+//   - [MimeReject]: refuse to process the resource,
+//   - [MimeRender]: let the browser render it and take a screenshot.
+//
+// `aPolicy` is a comma separated list of `type=action` entries like
+// `image/webp=download,application/pdf=render`; a type may use a
+// wildcard for its subtype (`image/*`) or be `*/*`. The entries are
+// checked before the built-in policy which renders HTML and text,
+// downloads GIF, JPEG, PNG and SVG images, converts BMP, TIFF and WebP
+// images, renders other images, and rejects audio, video, fonts, and
+// (most) `application/*` types.
+// Invalid entries are ignored.
+//
+// Parameters:
+//   - `aPolicy`: The comma separated list of `type=action` entries.
+func SetMimePolicy(aPolicy string) {
+	ssOptions.MimePolicy = normaliseMimePolicy(aPolicy)
+} // SetMimePolicy()
+
+/* _EoF_ */
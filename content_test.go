@@ -0,0 +1,170 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var (
+	// The first bytes of a PDF document:
+	pdfHead = []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	// The first bytes of a PNG image:
+	pngHead = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+)
+
+func Test_mimeType(t *testing.T) {
+	tests := []struct {
+		name      string
+		aDeclared string
+		aHead     []byte
+		aExt      string
+		want      string
+	}{
+		{"1", "", nil, "", ""},
+		{"2", "text/html; charset=UTF-8", nil, "", "text/html"},
+		{"3", "Image/PNG", nil, ".jpg", "image/png"},
+		{"4", "application/octet-stream", pdfHead, "", "application/pdf"},
+		{"5", "", pngHead, "", "image/png"},
+		{"6", "text/plain", pngHead, ".txt", "image/png"},
+		{"7", "application/octet-stream", []byte("\x00\x01\x02"), ".pdf", "application/pdf"},
+		{"8", "application/octet-stream", []byte("\x00\x01\x02"), "", "application/octet-stream"},
+		{"9", "", []byte("just some text"), "", "text/plain"},
+		{"10", "", nil, ".png", "image/png"},
+		{"11", "invalid", nil, "", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mimeType(tt.aDeclared, tt.aHead, tt.aExt); got != tt.want {
+				t.Errorf("%q: mimeType() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_mimeType()
+
+func Test_mimeAction(t *testing.T) {
+	defer func(aPolicy string) {
+		ssOptions.MimePolicy = aPolicy
+	}(ssOptions.MimePolicy)
+
+	tests := []struct {
+		name    string
+		aPolicy string
+		aType   string
+		want    string
+	}{
+		{"1", "", "", MimeRender},
+		{"2", "", "text/html", MimeRender},
+		{"3", "", "application/xhtml+xml", MimeRender},
+		{"4", "", "application/pdf", MimeReject},
+		{"5", "", "image/png", MimeDownload},
+		{"6", "", "image/webp", MimeConvert},
+		{"7", "", "image/avif", MimeRender},
+		{"8", "", "video/mp4", MimeReject},
+		{"9", "", "text/csv", MimeReject},
+		{"10", "application/pdf=render", "application/pdf", MimeRender},
+		{"11", "image/*=reject", "image/png", MimeReject},
+		{"12", "*/*=reject", "text/html", MimeReject},
+		{"13", "image/webp=download", "image/webp", MimeDownload},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMimePolicy(tt.aPolicy)
+			if got := mimeAction(ssOptions, tt.aType); got != tt.want {
+				t.Errorf("%q: mimeAction() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_mimeAction()
+
+func TestSetMimePolicy(t *testing.T) {
+	defer func(aPolicy string) {
+		ssOptions.MimePolicy = aPolicy
+	}(ssOptions.MimePolicy)
+
+	tests := []struct {
+		name    string
+		aPolicy string
+		want    string
+	}{
+		{"1", "", ""},
+		{"2", "image/webp", ""},
+		{"3", "image/webp=show", ""},
+		{"4", "/webp=render,image/=render", ""},
+		{"5", " Image/WebP = Download , application/pdf=render", "application/pdf=render,image/webp=download"},
+		{"6", "*/*=reject,foo", "*/*=reject"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMimePolicy(tt.aPolicy)
+			if got := MimePolicy(); got != tt.want {
+				t.Errorf("%q: SetMimePolicy() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // TestSetMimePolicy()
+
+func Test_contentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(aWriter http.ResponseWriter, aRequest *http.Request) {
+		if "bytes=0-511" != aRequest.Header.Get("Range") {
+			http.Error(aWriter, "missing range", http.StatusBadRequest)
+			return
+		}
+		switch aRequest.URL.Path {
+		case "/download":
+			aWriter.Header().Set("Content-Type", "application/octet-stream")
+			aWriter.WriteHeader(http.StatusPartialContent)
+			_, _ = aWriter.Write(pdfHead)
+
+		case "/img/123":
+			aWriter.Header().Set("Content-Type", "text/plain")
+			_, _ = aWriter.Write(pngHead)
+
+		case "/page":
+			aWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = aWriter.Write([]byte("<html><body>Hello</body></html>"))
+
+		default:
+			http.NotFound(aWriter, aRequest)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		aURL string
+		want string
+	}{
+		{"1", server.URL + "/download?id=3", "application/pdf"},
+		{"2", server.URL + "/img/123", "image/png"},
+		{"3", server.URL + "/page", "text/html"},
+		{"4", server.URL + "/missing", "text/plain"},
+		{"5", server.URL + "/missing.png", "image/png"},
+		{"6", "http://127.0.0.1:1/page.pdf", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentType(context.Background(), ssOptions, tt.aURL); got != tt.want {
+				t.Errorf("%q: contentType() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_contentType()
+
+/* _EoF_ */
@@ -0,0 +1,381 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+The cookie file is either a Netscape `cookies.txt` file (as written by
+e.g. `curl` or `wget`) or a JSON file with a list of cookie objects (as
+exported by browser extensions, Puppeteer or Playwright).
+
+A cookie whose domain starts with a dot applies to that domain and all
+its subdomains; otherwise it applies to the named host only.
+*/
+
+type (
+	// `tCookieJar` caches the cookies read from a cookie file.
+	tCookieJar struct {
+		sync.Mutex
+
+		// The cookies read from the file:
+		cookies []*http.Cookie
+
+		// Name of the file the cookies were read from:
+		filename string
+
+		// Time of next reading the cookie file:
+		nextTime time.Time
+	}
+
+	// `tJSONCookie` is a single cookie of a JSON cookie file.
+	tJSONCookie struct {
+		Domain         string  `json:"domain"`
+		ExpirationDate float64 `json:"expirationDate"` // browser extensions
+		Expires        float64 `json:"expires"`        // Puppeteer, Playwright
+		HTTPOnly       bool    `json:"httpOnly"`
+		Name           string  `json:"name"`
+		Path           string  `json:"path"`
+		SameSite       string  `json:"sameSite"`
+		Secure         bool    `json:"secure"`
+		Value          string  `json:"value"`
+	}
+)
+
+var (
+	// The cookies of the cookie file:
+	ssCookieJar tCookieJar
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `cookieTasks()` returns the browser actions loading the cookies
+// of the cookie file into the browser.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform before navigation.
+func cookieTasks() chromedp.Tasks {
+	cookies := ssCookieJar.list()
+	if 0 == len(cookies) {
+		return nil
+	}
+
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, cookie := range cookies {
+		param := &network.CookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+		}
+		if strings.HasPrefix(cookie.Domain, ".") {
+			param.Domain = cookie.Domain
+		} else { // host-only cookie
+			param.URL = cookieURL(cookie).String()
+		}
+		if !cookie.Expires.IsZero() {
+			expires := cdp.TimeSinceEpoch(cookie.Expires)
+			param.Expires = &expires
+		}
+		switch cookie.SameSite {
+		case http.SameSiteLaxMode:
+			param.SameSite = network.CookieSameSiteLax
+		case http.SameSiteNoneMode:
+			param.SameSite = network.CookieSameSiteNone
+		case http.SameSiteStrictMode:
+			param.SameSite = network.CookieSameSiteStrict
+		}
+		params = append(params, param)
+	}
+
+	return chromedp.Tasks{
+		network.SetCookies(params),
+	}
+} // cookieTasks()
+
+// `cookieURL()` returns the URL `aCookie` applies to.
+//
+// Parameters:
+//   - `aCookie`: The cookie to process.
+//
+// Returns:
+//   - `*url.URL`: The cookie's URL.
+func cookieURL(aCookie *http.Cookie) *url.URL {
+	result := &url.URL{
+		Scheme: "http",
+		Host:   strings.TrimPrefix(aCookie.Domain, "."),
+		Path:   aCookie.Path,
+	}
+	if aCookie.Secure {
+		result.Scheme = "https"
+	}
+
+	return result
+} // cookieURL()
+
+// `httpJar()` returns a cookie jar with the cookies of the cookie file
+// for use by direct downloads.
+//
+// Returns:
+//   - `http.CookieJar`: The cookie jar or `nil` if there are no cookies.
+func httpJar() http.CookieJar {
+	cookies := ssCookieJar.list()
+	if 0 == len(cookies) {
+		return nil
+	}
+
+	jar, err := cookiejar.New(nil)
+	if nil != err {
+		return nil
+	}
+	for _, cookie := range cookies {
+		c := *cookie
+		if !strings.HasPrefix(c.Domain, ".") {
+			c.Domain = "" // host-only cookie
+		}
+		jar.SetCookies(cookieURL(cookie), []*http.Cookie{&c})
+	}
+
+	return jar
+} // httpJar()
+
+// `readCookieFile()` reads the named cookie file and returns its
+// (not yet expired) cookies.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to read.
+//
+// Returns:
+//   - `[]*http.Cookie`: The list of cookies read from `aFilename`.
+func readCookieFile(aFilename string) []*http.Cookie {
+	if 0 == len(aFilename) {
+		return nil
+	}
+
+	data, err := os.ReadFile(aFilename) // #nosec G304
+	if nil != err {
+		return nil
+	}
+	if data = bytes.TrimSpace(data); 0 == len(data) {
+		return nil
+	}
+
+	var cookies []*http.Cookie
+	if ('[' == data[0]) || ('{' == data[0]) {
+		cookies = readJSONCookies(data)
+	} else {
+		cookies = readNetscapeCookies(data)
+	}
+
+	now := time.Now()
+	result := cookies[:0]
+	for _, cookie := range cookies {
+		if (0 == len(cookie.Name)) || (0 == len(cookie.Domain)) {
+			continue // invalid cookie
+		}
+		if !cookie.Expires.IsZero() && cookie.Expires.Before(now) {
+			continue // expired cookie
+		}
+		if 0 == len(cookie.Path) {
+			cookie.Path = "/"
+		}
+		cookie.Domain = strings.ToLower(cookie.Domain)
+		result = append(result, cookie)
+	}
+
+	return result
+} // readCookieFile()
+
+// `readJSONCookies()` returns the cookies of the JSON data `aData`.
+//
+// Both a plain list of cookies and an object with a `cookies` list
+// (like Playwright's storage state) are accepted.
+//
+// Parameters:
+//   - `aData`: The JSON data to parse.
+//
+// Returns:
+//   - `[]*http.Cookie`: The list of cookies.
+func readJSONCookies(aData []byte) (rList []*http.Cookie) {
+	var list []tJSONCookie
+	if '{' == aData[0] {
+		var state struct {
+			Cookies []tJSONCookie `json:"cookies"`
+		}
+		if err := json.Unmarshal(aData, &state); nil != err {
+			return
+		}
+		list = state.Cookies
+	} else if err := json.Unmarshal(aData, &list); nil != err {
+		return
+	}
+
+	for _, jc := range list {
+		cookie := &http.Cookie{
+			Domain:   jc.Domain,
+			HttpOnly: jc.HTTPOnly,
+			Name:     jc.Name,
+			Path:     jc.Path,
+			Secure:   jc.Secure,
+			Value:    jc.Value,
+		}
+		if expires := max(jc.ExpirationDate, jc.Expires); 0 < expires {
+			cookie.Expires = time.Unix(int64(expires), 0)
+		}
+		switch strings.ToLower(jc.SameSite) {
+		case "lax":
+			cookie.SameSite = http.SameSiteLaxMode
+		case "none", "no_restriction":
+			cookie.SameSite = http.SameSiteNoneMode
+		case "strict":
+			cookie.SameSite = http.SameSiteStrictMode
+		}
+		rList = append(rList, cookie)
+	}
+
+	return
+} // readJSONCookies()
+
+// `readNetscapeCookies()` returns the cookies of the Netscape
+// `cookies.txt` data `aData`.
+//
+// Each line consists of seven TAB separated fields: domain,
+// subdomains flag, path, secure flag, expiry (Unix time), name and
+// value. Lines prefixed with `#HttpOnly_` denote HTTP-only cookies,
+// other lines starting with `#` are comments.
+//
+// Parameters:
+//   - `aData`: The file's data to parse.
+//
+// Returns:
+//   - `[]*http.Cookie`: The list of cookies.
+func readNetscapeCookies(aData []byte) (rList []*http.Cookie) {
+	for _, line := range strings.Split(string(aData), "\n") {
+		httpOnly := false
+		line = strings.TrimRight(line, "\r")
+		if after, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line, httpOnly = after, true
+		}
+		if (0 == len(strings.TrimSpace(line))) || (`#` == line[0:1]) {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if 7 > len(fields) {
+			continue // invalid line
+		}
+
+		domain := strings.TrimSpace(fields[0])
+		if "TRUE" == strings.ToUpper(fields[1]) {
+			if !strings.HasPrefix(domain, ".") {
+				domain = "." + domain
+			}
+		} else {
+			domain = strings.TrimPrefix(domain, ".")
+		}
+		cookie := &http.Cookie{
+			Domain:   domain,
+			HttpOnly: httpOnly,
+			Name:     fields[5],
+			Path:     fields[2],
+			Secure:   "TRUE" == strings.ToUpper(fields[3]),
+			Value:    strings.Join(fields[6:], "\t"),
+		}
+		if expires, err := strconv.ParseInt(fields[4], 10, 64); (nil == err) && (0 < expires) {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+		rList = append(rList, cookie)
+	}
+
+	return
+} // readNetscapeCookies()
+
+// `list()` returns the current list of cookies, re-reading the cookie
+// file if [ReadWaitTime] has passed since it was last read.
+//
+// Returns:
+//   - `[]*http.Cookie`: The current list of cookies.
+func (cj *tCookieJar) list() []*http.Cookie {
+	cj.Lock()
+	defer cj.Unlock()
+
+	if 0 == len(cj.filename) {
+		return nil
+	}
+
+	if (0 == len(cj.cookies)) || time.Now().After(cj.nextTime) {
+		if 0 < ssReadWaitTime {
+			cj.nextTime = time.Now().Add(time.Duration(ssReadWaitTime) * time.Minute)
+		}
+		cj.cookies = readCookieFile(cj.filename)
+	}
+
+	return cj.cookies
+} // list()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `CookieFile()` returns the name of the file containing the cookies
+// to load before retrieving a web page.
+//
+// Returns:
+//   - `string`: The path/file name of the cookie file.
+func CookieFile() string {
+	return ssOptions.CookieFile
+} // CookieFile()
+
+// `SetCookieFile()` configures the name of the file containing the
+// cookies to load before retrieving a web page, e.g. to take
+// screenshots of pages behind a login.
+//
+// The file can be either a Netscape `cookies.txt` file or a JSON file
+// with a list of cookie objects (with the fields `domain`, `name`,
+// `value`, `path`, `secure`, `httpOnly`, `sameSite`, and
+// `expirationDate` or `expires`).
+// Each cookie is sent only to the domain (and path) it belongs to;
+// the cookies are used by the browser as well as for direct downloads.
+// The file is re-read according to the [ReadWaitTime] setting.
+//
+// NOTE: The [Cookies] option only controls the pages' access to
+// cookies by JavaScript (`document.cookie`), not the loading of this
+// file.
+// An invalid filename disables the feature.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the cookie file.
+func SetCookieFile(aFilename string) {
+	ssCookieJar.Lock()
+	defer ssCookieJar.Unlock()
+
+	ssCookieJar.filename, ssCookieJar.cookies = "", nil
+	if aFilename = strings.TrimSpace(aFilename); 0 < len(aFilename) {
+		ssCookieJar.filename, _ = stat(aFilename)
+	}
+	ssOptions.CookieFile = ssCookieJar.filename
+} // SetCookieFile()
+
+/* _EoF_ */
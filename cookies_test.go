@@ -0,0 +1,112 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net/url"
+	"os"
+	"testing"
+)
+
+func Test_httpJar(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.cookies"
+	jar := "# Netscape HTTP Cookie File\n" +
+		".example.com\tTRUE\t/\tFALSE\t0\tdomain\tall\n" +
+		"#HttpOnly_wiki.example.org\tFALSE\t/\tTRUE\t4102444800\tsession\tabc\n" +
+		"old.example.net\tFALSE\t/\tFALSE\t1\texpired\tgone\n"
+	writeFile(fName, []byte(jar), nil)
+	defer func() {
+		_ = os.Remove(fName)
+		SetCookieFile("")
+	}()
+	SetCookieFile(fName)
+
+	tests := []struct {
+		name string
+		aURL string
+		want string
+	}{
+		{"1", "https://example.com/", "domain=all"},
+		{"2", "http://www.example.com/page", "domain=all"},
+		{"3", "https://wiki.example.org/", "session=abc"},
+		{"4", "http://wiki.example.org/", ""},
+		{"5", "https://www.wiki.example.org/", ""},
+		{"6", "http://old.example.net/", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			URL, _ := url.Parse(tt.aURL)
+			got := ""
+			for _, cookie := range httpJar().Cookies(URL) {
+				got += cookie.String()
+			}
+			if got != tt.want {
+				t.Errorf("%q: httpJar() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_httpJar()
+
+func Test_readCookieFile(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.cookies"
+	defer func() {
+		_ = os.Remove(fName)
+	}()
+
+	tests := []struct {
+		name  string
+		aData string
+		want  int
+	}{
+		{"1", "", 0},
+		{"2", "\n", 0},
+		{"3", " \t\r\n\n ", 0},
+		{"4", ".example.com\tTRUE\t/\tFALSE\t0\tdomain\tall\n", 1},
+		{"5", "\n  [{\"domain\": \"example.org\", \"name\": \"b\", \"value\": \"2\"}]\n", 1},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFile(fName, []byte(tt.aData), nil)
+			if got := len(readCookieFile(fName)); got != tt.want {
+				t.Errorf("%q: readCookieFile() = %d cookies, want %d",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_readCookieFile()
+
+func Test_readJSONCookies(t *testing.T) {
+	j1 := `[{"domain": ".example.com", "name": "a", "value": "1", "path": "/", "expirationDate": 4102444800.5, "sameSite": "no_restriction"}]`
+	j2 := `{"cookies": [{"domain": "example.org", "name": "b", "value": "2", "expires": -1, "httpOnly": true, "secure": true, "sameSite": "Lax"}]}`
+	tests := []struct {
+		name  string
+		aData string
+		want  string
+	}{
+		{"1", j1, "a=1; Path=/; Domain=example.com; Expires=Fri, 01 Jan 2100 00:00:00 GMT; SameSite=None"},
+		{"2", j2, "b=2; Domain=example.org; HttpOnly; Secure; SameSite=Lax"},
+		{"3", `[{"name": `, ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			for _, cookie := range readJSONCookies([]byte(tt.aData)) {
+				got += cookie.String()
+			}
+			if got != tt.want {
+				t.Errorf("%q: readJSONCookies() = %q,\nwant %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_readJSONCookies()
+
+/* _EoF_ */
@@ -0,0 +1,347 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+A host pattern (as used in the Avoid/Need JavaScript lists and the
+host rules files) has one of the following forms:
+
+	*                   all hosts
+	example.com         the domain and all its subdomains
+	.example.com        the subdomains of the domain only
+	=www.example.com    exactly that host
+	*.cdn.*             a glob with `*` (any characters) and `?` (one
+	                    character) wildcards
+	re:^img\d+\.        a regular expression matched against the host
+
+Except for regular expressions a pattern may be followed by a port
+and/or a path prefix like
+
+	example.com:8080
+	example.com/blog
+	=www.example.com:8443/app/
+
+in which case only URLs with that port and/or a path starting with
+that prefix (at a `/` boundary) are matched.
+
+The hosts of both the patterns and the URLs are normalised (see
+`normaliseHost()`), so `bücher.de` matches `xn--bcher-kva.de` and
+vice versa. Regular expressions are matched against the normalised
+(i.e. ASCII) form of a host.
+*/
+
+const (
+	// Match all hosts.
+	patternAll tPatternKind = iota
+
+	// Match the domain and all its subdomains.
+	patternDomain
+
+	// Match exactly one host.
+	patternExact
+
+	// Match hosts by a glob or regular expression.
+	patternRegex
+
+	// Match the subdomains of a domain only.
+	patternSubdomains
+)
+
+var (
+	// The IDNA profile used to normalise hostnames; it's lenient
+	// regarding characters (like `_`) not allowed by the standard
+	// but used in the wild.
+	ssIDNA = idna.New(
+		idna.MapForLookup(),
+		idna.StrictDomainName(false),
+		idna.Transitional(false),
+	)
+)
+
+type (
+	// `tPatternKind` determines how a host pattern is matched.
+	tPatternKind int
+
+	// `tHostPattern` is a compiled host pattern.
+	tHostPattern struct {
+		// The hostname/domain to match (lowercased).
+		host string
+
+		// How the `host` is matched.
+		kind tPatternKind
+
+		// The path prefix to match (empty for all paths).
+		path string
+
+		// The port to match (empty for all ports).
+		port string
+
+		// The compiled glob or regular expression.
+		re *regexp.Regexp
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `defaultPort()` returns the default port of the URL scheme `aScheme`.
+//
+// Parameters:
+//   - `aScheme`: The (lowercase) URL scheme.
+//
+// Returns:
+//   - `string`: The scheme's default port or an empty string.
+func defaultPort(aScheme string) string {
+	switch aScheme {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	}
+
+	return ""
+} // defaultPort()
+
+// `normaliseGlob()` normalises the labels of the glob pattern
+// `aGlob` which don't contain any wildcards.
+//
+// Parameters:
+//   - `aGlob`: The glob pattern to normalise.
+//
+// Returns:
+//   - `string`: The normalised glob pattern.
+func normaliseGlob(aGlob string) string {
+	labels := strings.Split(strings.TrimRight(aGlob, "."), ".")
+	for idx, label := range labels {
+		if (0 < len(label)) && !strings.ContainsAny(label, "*?") {
+			labels[idx] = normaliseHost(label)
+		}
+	}
+
+	return strings.Join(labels, ".")
+} // normaliseGlob()
+
+// `normaliseHost()` returns the canonical form of the hostname `aHost`.
+//
+// Internationalised domain names are converted to their (lowercase)
+// ASCII/punycode form, trailing dots are removed, and IPv6 literals
+// (with or without brackets) are returned in their canonical notation
+// without brackets.
+//
+// Parameters:
+//   - `aHost`: The hostname to normalise.
+//
+// Returns:
+//   - `string`: The normalised hostname.
+func normaliseHost(aHost string) string {
+	host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(aHost), "["), "]")
+	if host = strings.TrimRight(host, "."); 0 == len(host) {
+		return ""
+	}
+	if addr, err := netip.ParseAddr(host); nil == err {
+		return addr.String()
+	}
+	if ascii, err := ssIDNA.ToASCII(host); nil == err {
+		return ascii
+	}
+
+	return strings.ToLower(host)
+} // normaliseHost()
+
+// `newHostPattern()` compiles the host pattern `aPattern`.
+//
+// Parameters:
+//   - `aPattern`: The host pattern to compile.
+//
+// Returns:
+//   - `*tHostPattern`: The compiled pattern or `nil` if `aPattern` is invalid.
+func newHostPattern(aPattern string) *tHostPattern {
+	if aPattern = strings.TrimSpace(aPattern); 0 == len(aPattern) {
+		return nil
+	}
+
+	if expr, ok := strings.CutPrefix(aPattern, "re:"); ok {
+		re, err := regexp.Compile(`(?i)` + expr)
+		if nil != err {
+			return nil
+		}
+		return &tHostPattern{kind: patternRegex, re: re}
+	}
+
+	result := &tHostPattern{}
+	if idx := strings.IndexByte(aPattern, '/'); 0 <= idx {
+		// Match the path the way it appears in (escaped) URLs:
+		aPattern, result.path = aPattern[:idx],
+			strings.ToLower((&url.URL{Path: aPattern[idx:]}).EscapedPath())
+	}
+	if idx := strings.LastIndexByte(aPattern, ':'); (0 <= idx) &&
+		((1 == strings.Count(aPattern, ":")) || (']' == aPattern[idx-1])) {
+		aPattern, result.port = aPattern[:idx], aPattern[idx+1:]
+	}
+	// IPv6 addresses are enclosed in brackets:
+	aPattern = strings.TrimSuffix(strings.TrimPrefix(aPattern, "["), "]")
+
+	switch {
+	case `*` == aPattern:
+		result.kind = patternAll
+
+	case strings.HasPrefix(aPattern, "="):
+		result.kind, result.host = patternExact, normaliseHost(aPattern[1:])
+
+	case strings.ContainsAny(aPattern, "*?"):
+		result.kind, result.re = patternRegex, wildcardRE(normaliseGlob(aPattern))
+
+	case strings.HasPrefix(aPattern, "."):
+		if host := normaliseHost(aPattern[1:]); 0 < len(host) {
+			result.kind, result.host = patternSubdomains, "."+host
+		}
+
+	default:
+		result.kind, result.host = patternDomain, normaliseHost(aPattern)
+	}
+	if (patternAll != result.kind) && (nil == result.re) && (0 == len(result.host)) {
+		return nil
+	}
+
+	return result
+} // newHostPattern()
+
+// `hostPatterns()` compiles the host patterns of `aList`, skipping
+// invalid ones.
+//
+// Parameters:
+//   - `aList`: The host patterns to compile.
+//
+// Returns:
+//   - `[]*tHostPattern`: The compiled patterns.
+func hostPatterns(aList []string) (rList []*tHostPattern) {
+	for _, line := range aList {
+		if (0 == len(line)) || (`#` == line[0:1]) {
+			continue
+		}
+		if pattern := newHostPattern(line); nil != pattern {
+			rList = append(rList, pattern)
+		}
+	}
+
+	return
+} // hostPatterns()
+
+// `urlParts()` returns the normalised host (see `normaliseHost()`),
+// the port (or the scheme's default port), and the path of `aURL`.
+//
+// If `aURL` isn't a full URL but just a hostname (optionally with
+// a port and path) no default port is assumed.
+//
+// Parameters:
+//   - `aURL`: The URL to split.
+//
+// Returns:
+//   - `string`: The URL's hostname.
+//   - `string`: The URL's port.
+//   - `string`: The URL's path.
+func urlParts(aURL string) (rHost, rPort, rPath string) {
+	if !strings.Contains(aURL, "://") {
+		// The given `aURL` is obviously not a full/correct URL
+		// but probably just a host name.
+		URL, err := url.Parse("//" + strings.TrimPrefix(aURL, "//"))
+		if nil != err {
+			return
+		}
+		return normaliseHost(URL.Hostname()), URL.Port(), URL.EscapedPath()
+	}
+
+	URL, err := url.Parse(aURL)
+	if nil != err {
+		return
+	}
+	if rHost = normaliseHost(URL.Hostname()); 0 == len(rHost) {
+		return
+	}
+
+	if rPort = URL.Port(); 0 == len(rPort) {
+		rPort = defaultPort(URL.Scheme)
+	}
+	if rPath = URL.EscapedPath(); 0 == len(rPath) {
+		rPath = "/"
+	}
+
+	return
+} // urlParts()
+
+// `matches()` returns whether the host `aHost` with port `aPort`
+// and path `aPath` is matched by the pattern.
+//
+// If the pattern requires a port or path but the respective argument
+// is empty the pattern doesn't match.
+//
+// Parameters:
+//   - `aHost`: The (lowercased) hostname to check.
+//   - `aPort`: The port to check (may be empty).
+//   - `aPath`: The path to check (may be empty).
+//
+// Returns:
+//   - `bool`: Whether the pattern matches.
+func (hp *tHostPattern) matches(aHost, aPort, aPath string) bool {
+	if (0 < len(hp.port)) && (hp.port != aPort) {
+		return false
+	}
+	if 0 < len(hp.path) {
+		if !strings.HasPrefix(strings.ToLower(aPath), hp.path) {
+			return false
+		}
+		// The prefix must end at a path segment's boundary:
+		if (len(aPath) > len(hp.path)) && !strings.HasSuffix(hp.path, "/") &&
+			('/' != aPath[len(hp.path)]) {
+			return false
+		}
+	}
+
+	switch hp.kind {
+	case patternAll:
+		return true
+
+	case patternExact:
+		return aHost == hp.host
+
+	case patternRegex:
+		return (0 < len(aHost)) && hp.re.MatchString(aHost)
+
+	case patternSubdomains:
+		return strings.HasSuffix(aHost, hp.host)
+
+	default:
+		return (aHost == hp.host) || strings.HasSuffix(aHost, "."+hp.host)
+	}
+} // matches()
+
+// `matchesURL()` returns whether `aURL` is matched by the pattern.
+//
+// Parameters:
+//   - `aURL`: The URL to check.
+//
+// Returns:
+//   - `bool`: Whether the pattern matches.
+func (hp *tHostPattern) matchesURL(aURL string) bool {
+	host, port, path := urlParts(aURL)
+
+	return hp.matches(host, port, path)
+} // matchesURL()
+
+/* _EoF_ */
@@ -0,0 +1,128 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"testing"
+)
+
+func Test_newHostPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		aPattern string
+		wantNil  bool
+		wantKind tPatternKind
+		wantPort string
+		wantPath string
+	}{
+		{"1", "", true, patternAll, "", ""},
+		{"2", "*", false, patternAll, "", ""},
+		{"3", "Example.COM", false, patternDomain, "", ""},
+		{"4", ".example.com", false, patternSubdomains, "", ""},
+		{"5", "=www.example.com:8443/App/", false, patternExact, "8443", "/app/"},
+		{"6", "*.cdn.*", false, patternRegex, "", ""},
+		{"7", `re:^img\d+\.`, false, patternRegex, "", ""},
+		{"8", "re:([", true, patternAll, "", ""},
+		{"9", "=", true, patternAll, "", ""},
+		{"10", "[::1]:8080", false, patternDomain, "8080", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newHostPattern(tt.aPattern)
+			if (nil == got) != tt.wantNil {
+				t.Errorf("%q: newHostPattern() = %v, wantNil %v",
+					tt.name, got, tt.wantNil)
+				return
+			}
+			if nil == got {
+				return
+			}
+			if (got.kind != tt.wantKind) || (got.port != tt.wantPort) || (got.path != tt.wantPath) {
+				t.Errorf("%q: newHostPattern() = {%d %q %q}, want {%d %q %q}",
+					tt.name, got.kind, got.port, got.path,
+					tt.wantKind, tt.wantPort, tt.wantPath)
+			}
+		})
+	}
+} // Test_newHostPattern()
+
+func Test_normaliseHost(t *testing.T) {
+	tests := []struct {
+		name  string
+		aHost string
+		want  string
+	}{
+		{"1", "", ""},
+		{"2", "Example.COM.", "example.com"},
+		{"3", "Bücher.de", "xn--bcher-kva.de"},
+		{"4", "xn--bcher-kva.de", "xn--bcher-kva.de"},
+		{"5", "[2001:DB8:0::1]", "2001:db8::1"},
+		{"6", "my_host.example.com", "my_host.example.com"},
+		{"7", "127.0.0.1", "127.0.0.1"},
+		{"8", ".", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normaliseHost(tt.aHost); got != tt.want {
+				t.Errorf("%q: normaliseHost() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_normaliseHost()
+
+func Test_tHostPattern_matchesURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		aPattern string
+		aURL     string
+		want     bool
+	}{
+		{"1", "*", "https://www.example.com/", true},
+		{"2", "example.com", "https://example.com/", true},
+		{"3", "example.com", "https://www.example.com/", true},
+		{"4", "example.com", "https://badexample.com/", false},
+		{"5", ".example.com", "https://example.com/", false},
+		{"6", ".example.com", "https://www.example.com/", true},
+		{"7", "=example.com", "https://www.example.com/", false},
+		{"8", "=www.example.com", "https://www.example.com/", true},
+		{"9", "*.cdn.*", "https://img.cdn.example.net/x.png", true},
+		{"10", "*.cdn.*", "https://cdn.example.net/", false},
+		{"11", `re:^img\d+\.example\.com$`, "https://IMG42.example.com/", true},
+		{"12", `re:^img\d+\.example\.com$`, "https://imgx.example.com/", false},
+		{"13", "example.com/blog", "https://www.example.com/blog/2025/", true},
+		{"14", "example.com/blog", "https://www.example.com/blog", true},
+		{"15", "example.com/blog", "https://www.example.com/blogger", false},
+		{"16", "example.com/blog", "https://www.example.com/", false},
+		{"17", "example.com:8080", "http://example.com:8080/", true},
+		{"18", "example.com:8080", "http://example.com/", false},
+		{"19", "example.com:443", "https://example.com/", true},
+		{"20", "example.com", "example.com", true},
+		{"21", "[::1]:8080", "http://[::1]:8080/", true},
+		{"22", "bücher.de", "https://www.xn--bcher-kva.de/", true},
+		{"23", "=xn--bcher-kva.de", "https://BÜCHER.de./", true},
+		{"24", "*.bücher.*", "https://shop.xn--bcher-kva.de/", true},
+		{"25", "[0:0::1]", "http://[::1]:8080/", true},
+		{"26", "example.com/Straße", "https://example.com/Stra%C3%9Fe/x", true},
+		{"27", "example.com:8080", "example.com:8080", true},
+		{"28", "example.com:8080", "example.com", false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := newHostPattern(tt.aPattern)
+			if got := pattern.matchesURL(tt.aURL); got != tt.want {
+				t.Errorf("%q: tHostPattern.matchesURL() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_tHostPattern_matchesURL()
+
+/* _EoF_ */
@@ -0,0 +1,267 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+A host rules file consists of lines with three fields:
+
+	HOST ACTION ARGUMENT
+
+`HOST` is either `*` (i.e. all hosts) or a host pattern matched the
+same way as the entries in the Avoid/Need JavaScript lists (see
+`hostpattern.go`).
+`ACTION` is a keyword whose meaning depends on the respective file.
+`ARGUMENT` is the rest of the line; unlike the other two fields it's
+used case-sensitive.
+Empty lines and lines starting with `#` are ignored.
+*/
+
+type (
+	// `tHostRule` is a single line of a host rules file.
+	tHostRule struct {
+		// The rule's keyword (lowercased).
+		action string
+
+		// The rule's argument.
+		arg string
+
+		// The host/domain pattern the rule applies to (see `ruleHost()`).
+		host string
+
+		// The compiled `host` pattern.
+		pattern *tHostPattern
+	}
+
+	// `tHostRules` caches the rules read from a host rules file.
+	tHostRules struct {
+		sync.Mutex
+
+		// Name of the file the rules were read from:
+		filename string
+
+		// Name of the rules list (like [RulesStyles]):
+		kind string
+
+		// List of rules to test against:
+		list []tHostRule
+
+		// Time of next reading the rules file:
+		nextTime time.Time
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `hostOf()` returns the normalised hostname of `aURL`
+// (see `normaliseHost()`).
+//
+// Parameters:
+//   - `aURL`: The URL to process.
+//
+// Returns:
+//   - `string`: The URL's hostname.
+func hostOf(aURL string) string {
+	URL, err := url.Parse(aURL)
+	if nil != err {
+		return ""
+	}
+
+	return normaliseHost(URL.Hostname())
+} // hostOf()
+
+// `readRulesFile()` reads the named host rules file and returns
+// its rules.
+//
+// Lines with less than three fields are ignored.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to read.
+//
+// Returns:
+//   - `[]tHostRule`: The list of rules read from `aFilename`.
+func readRulesFile(aFilename string) (rList []tHostRule) {
+	if 0 == len(aFilename) {
+		return
+	}
+
+	data, err := os.ReadFile(aFilename) // #nosec G304
+	if (nil != err) || (0 == len(data)) {
+		return
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); (0 == len(line)) || (`#` == line[0:1]) {
+			continue
+		}
+		fields := strings.Fields(line)
+		if 3 > len(fields) {
+			continue // invalid line
+		}
+
+		// The argument is the rest of the line after the action:
+		arg := strings.TrimSpace(line[len(fields[0]):])
+		arg = strings.TrimSpace(arg[len(fields[1]):])
+
+		pattern := newHostPattern(fields[0])
+		if nil == pattern {
+			continue // invalid host pattern
+		}
+		rList = append(rList, tHostRule{
+			action:  strings.ToLower(fields[1]),
+			arg:     arg,
+			host:    ruleHost(fields[0]),
+			pattern: pattern,
+		})
+	}
+
+	return
+} // readRulesFile()
+
+// `relPath()` returns `aPathname` relative to the directory of the
+// rules file `aRulesFile` unless it's an absolute path.
+//
+// Parameters:
+//   - `aRulesFile`: The path/file name of the rules file.
+//   - `aPathname`: The path/file name given in the rules file.
+//
+// Returns:
+//   - `string`: The path/file name to use.
+func relPath(aRulesFile, aPathname string) string {
+	if filepath.IsAbs(aPathname) {
+		return aPathname
+	}
+
+	return filepath.Join(filepath.Dir(aRulesFile), aPathname)
+} // relPath()
+
+// `ruleHost()` returns the host pattern `aHost` the way it's stored
+// in a rule, i.e. lowercased unless it's a regular expression (`re:…`)
+// whose meaning depends on the case (like `\d` vs. `\D`).
+//
+// Parameters:
+//   - `aHost`: The host pattern of the rule.
+//
+// Returns:
+//   - `string`: The host pattern to store.
+func ruleHost(aHost string) string {
+	if strings.HasPrefix(aHost, "re:") {
+		return aHost
+	}
+
+	return strings.ToLower(aHost)
+} // ruleHost()
+
+// `args()` returns the arguments of all rules with `anAction`
+// matching the host of `aURL`.
+//
+// Parameters:
+//   - `aURL`: The URL to check.
+//   - `anAction`: The (lowercase) rule keyword to look for.
+//
+// Returns:
+//   - `[]string`: The matching rules' arguments.
+func (hr *tHostRules) args(aURL, anAction string) (rList []string) {
+	host, port, path := urlParts(aURL)
+	for _, rule := range hr.rules() {
+		if (anAction == rule.action) && rule.pattern.matches(host, port, path) {
+			rList = append(rList, rule.arg)
+		}
+	}
+
+	return
+} // args()
+
+// `file()` returns the name of the rules file in use.
+//
+// Returns:
+//   - `string`: The path/file name of the rules file.
+func (hr *tHostRules) file() string {
+	hr.Lock()
+	defer hr.Unlock()
+
+	return hr.filename
+} // file()
+
+// `fileRules()` returns the current list of rules, re-reading the rules
+// file if [ReadWaitTime] has passed since it was last read.
+//
+// Returns:
+//   - `[]tHostRule`: The current list of rules read from the file.
+func (hr *tHostRules) fileRules() []tHostRule {
+	hr.Lock()
+	defer hr.Unlock()
+
+	if 0 == len(hr.filename) {
+		return nil
+	}
+
+	if (0 == len(hr.list)) || time.Now().After(hr.nextTime) {
+		if 0 < ssReadWaitTime {
+			hr.nextTime = time.Now().Add(time.Duration(ssReadWaitTime) * time.Minute)
+		}
+		hr.list = readRulesFile(hr.filename)
+	}
+
+	return hr.list
+} // fileRules()
+
+// `rules()` returns the current list of rules, i.e. the rules of the
+// installed [THostRuleProvider] (or the rules file) followed by the
+// rules added by [AddHostRule].
+//
+// Returns:
+//   - `[]tHostRule`: The current list of rules.
+func (hr *tHostRules) rules() []tHostRule {
+	var result []tHostRule
+	if nil == ssProvider {
+		result = hr.fileRules()
+	} else {
+		result = compileRules(ssProvider.HostRules(hr.kind))
+	}
+	if added := ssAddedRules.rules(hr.kind); 0 < len(added) {
+		result = append(slices.Clip(result), added...)
+	}
+
+	return result
+} // rules()
+
+// `setFile()` changes the rules file to use.
+//
+// An invalid filename disables the rules.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the rules file.
+//
+// Returns:
+//   - `string`: The complete path/file or an empty string.
+func (hr *tHostRules) setFile(aFilename string) string {
+	hr.Lock()
+	defer hr.Unlock()
+
+	hr.filename, hr.list = "", nil
+	if aFilename = strings.TrimSpace(aFilename); 0 < len(aFilename) {
+		hr.filename, _ = stat(aFilename)
+	}
+
+	return hr.filename
+} // setFile()
+
+/* _EoF_ */
@@ -0,0 +1,108 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_hostOf(t *testing.T) {
+	tests := []struct {
+		name string
+		aURL string
+		want string
+	}{
+		{"1", "", ""},
+		{"2", "https://www.Example.com/page", "www.example.com"},
+		{"3", "http://example.org:8080/", "example.org"},
+		{"4", "example.net", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostOf(tt.aURL); got != tt.want {
+				t.Errorf("%q: hostOf() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_hostOf()
+
+func Test_readRulesFile(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.rules"
+	list := `
+# Test rules
+
+*	before	Stub.js
+Example.COM after   Some Script.js
+re:^CDN\D	after	cdn.js
+invalid.line
+	# _EoF_
+`
+	writeFile(fName, []byte(list), nil)
+	defer func() {
+		_ = os.Remove(fName)
+	}()
+
+	n1 := ""
+	var w1 []tHostRule
+	//
+	n2 := "/dev/not/there"
+	w2 := w1
+	//
+	n3 := fName
+	w3 := []tHostRule{
+		{action: "before", arg: "Stub.js", host: "*", pattern: newHostPattern("*")},
+		{action: "after", arg: "Some Script.js", host: "example.com", pattern: newHostPattern("example.com")},
+		{action: "after", arg: "cdn.js", host: `re:^CDN\D`, pattern: newHostPattern(`re:^CDN\D`)},
+	}
+
+	tests := []struct {
+		name      string
+		aFilename string
+		wantRList []tHostRule
+	}{
+		{"1", n1, w1},
+		{"2", n2, w2},
+		{"3", n3, w3},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotRList := readRulesFile(tt.aFilename); !reflect.DeepEqual(gotRList, tt.wantRList) {
+				t.Errorf("%q: readRulesFile() = %v,\nwant %v",
+					tt.name, gotRList, tt.wantRList)
+			}
+		})
+	}
+} // Test_readRulesFile()
+
+func Test_relPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		aRulesFile string
+		aPathname  string
+		want       string
+	}{
+		{"1", "/etc/screenshot/scripts.list", "/usr/share/a.js", "/usr/share/a.js"},
+		{"2", "/etc/screenshot/scripts.list", "a.js", "/etc/screenshot/a.js"},
+		{"3", "/etc/screenshot/scripts.list", "../b/a.js", "/etc/b/a.js"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relPath(tt.aRulesFile, tt.aPathname); got != tt.want {
+				t.Errorf("%q: relPath() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_relPath()
+
+/* _EoF_ */
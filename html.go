@@ -0,0 +1,250 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"html/template"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Image rendered by the browser from a given HTML document.
+	SourceHTML = `html`
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `configHTML()` sets up how to take a screenshot of the HTML document
+// `aHTML` using the same emulation settings as for web pages.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aHTML`: The HTML document to render.
+//   - `aResult`: Data structure to receive the generated screenshot image.
+//
+// Returns:
+//   - `chromedp.Tasks`: A sequential list of Actions that can be used as a single Action.
+func configHTML(aOptions *TScreenshotParams, aHTML string, aResult *[]byte) chromedp.Tasks {
+	return append(configBrowser(aOptions, aOptions.JavaScript),
+		// start with an empty page …
+		chromedp.Navigate("about:blank"),
+		// … and replace its content by the given document:
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			tree, err := page.GetFrameTree().Do(aContext)
+			if nil != err {
+				return err
+			}
+
+			return page.SetDocumentContent(tree.Frame.ID, aHTML).Do(aContext)
+		}),
+		chromedp.Sleep(waitTime(aOptions, aOptions.JavaScript)), // time to render the page
+		chromedp.FullScreenshot(aResult, aOptions.ImageQuality),
+	)
+} // configHTML()
+
+// `createImageFromHTML()` renders the HTML document `aHTML` using the
+// options `aOptions` and stores the resulting image in [ImageDir]
+// (see [CreateImageFromHTML]).
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aHTML`: The HTML document to render.
+//   - `aName`: The name of the image file (without extension) to create.
+//
+// Returns:
+//   - `string`: The file name of the saved image.
+//   - `error`: A possible error during creation of the image.
+func createImageFromHTML(aOptions *TScreenshotParams, aHTML, aName string) (string, error) {
+	if 0 == len(ssOptions.ImageDir) {
+		return "", errors.New(ssLibName + ": property 'ImageDir' is empty")
+	}
+	result := htmlName(aName)
+	if 0 == len(result) {
+		return "", errors.New(ssLibName + ": invalid image name '" + aName + "'")
+	}
+	if 0 == len(strings.TrimSpace(aHTML)) {
+		return "", errors.New(ssLibName + ": empty HTML document for '" + aName + "'")
+	}
+
+	fName := filepath.Join(ssOptions.ImageDir, result)
+	if exists(aOptions, fName) {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		time.Duration(aOptions.MaxProcessTime)*time.Second)
+	defer cancel()
+
+	var rawData []byte
+	imageData, err := renderImage(ctx, aOptions, aName, configHTML(aOptions, aHTML, &rawData), &rawData)
+	if nil != err {
+		return "", err
+	}
+	if 0 == len(imageData) {
+		return "", errors.New(ssLibName + ": no data received for '" +
+			fName + "'")
+	}
+
+	if err = writeFile(fName, imageData, nil); nil != err {
+		return "", err
+	}
+
+	if aOptions.Sidecar {
+		capture := &TCaptureResult{
+			Filename: result,
+			Source:   SourceHTML,
+			Time:     time.Now(),
+		}
+		if err = writeSidecar(fName, capture); nil != err {
+			log.Println(ssLibName, err)
+		}
+	}
+
+	return result, nil
+} // createImageFromHTML()
+
+// `htmlName()` returns the image file name to use for `aName`.
+//
+// Any directory part and filename extension of `aName` are removed
+// and the configured [ImageType] is appended.
+//
+// Parameters:
+//   - `aName`: The caller provided name of the image.
+//
+// Returns:
+//   - `string`: The file name of the image.
+func htmlName(aName string) string {
+	if aName = strings.TrimSpace(aName); 0 == len(aName) {
+		return ""
+	}
+	aName = filepath.Base(aName)
+	aName = strings.TrimSuffix(aName, filepath.Ext(aName))
+	switch aName {
+	case "", ".", "..", string(filepath.Separator):
+		return ""
+	}
+
+	return aName + `.` + ImageType()
+} // htmlName()
+
+// `templateName()` returns the image name for the rendered template
+// document `aHTML` with the given viewport.
+//
+// The name is derived from a hash of the template's name, the rendered
+// document (i.e. the template plus its data) and the viewport so that
+// identical input results in the same name.
+//
+// Parameters:
+//   - `aTemplate`: The name of the template used.
+//   - `aHTML`: The rendered HTML document.
+//   - `aWidth`: The viewport's width.
+//   - `aHeight`: The viewport's height.
+//
+// Returns:
+//   - `string`: The name of the image (without extension).
+func templateName(aTemplate string, aHTML []byte, aWidth, aHeight int) string {
+	hash := sha256.New()
+	hash.Write([]byte(aTemplate))
+	hash.Write([]byte{0})
+	hash.Write(aHTML)
+	hash.Write([]byte{0})
+	hash.Write([]byte(strconv.Itoa(aWidth) + "x" + strconv.Itoa(aHeight)))
+
+	return "tpl" + hex.EncodeToString(hash.Sum(nil))[:32]
+} // templateName()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `CreateImageFromHTML()` renders the HTML document `aHTML` and stores
+// the resulting image in [ImageDir], returning the file name of the
+// saved image or an error in case of problems.
+//
+// The document is rendered with the same emulation settings (size,
+// scale, JavaScript, etc.) as web pages processed by [CreateImage].
+// Relative references in `aHTML` can't be resolved since the document
+// doesn't have a base URL; use absolute URLs or a `<base>` element.
+//
+// To render a local file use [CreateImage] with a `file://` URL.
+//
+// Like with [CreateImage] an already existing image (see [ImageAge]
+// and [ImageOverwrite]) is not replaced.
+//
+// Parameters:
+//   - `aHTML`: The HTML document to render.
+//   - `aName`: The name of the image file (without extension) to create.
+//
+// Returns:
+//   - `string`: The file name of the saved image.
+//   - `error`: A possible error during creation of the image.
+func CreateImageFromHTML(aHTML, aName string) (string, error) {
+	return createImageFromHTML(ssOptions, aHTML, aName)
+} // CreateImageFromHTML()
+
+// `CreateImageFromTemplate()` executes `aTemplate` with `aData`, renders
+// the resulting HTML document in a viewport of `aWidth` x `aHeight`
+// pixels and stores the image in [ImageDir], returning the file name of
+// the saved image or an error in case of problems.
+//
+// The image's name is derived from a hash of the template and its data
+// (and the viewport); hence calling this function again with identical
+// arguments reuses the already existing image file (see [ImageAge] and
+// [ImageOverwrite]) instead of rendering it again.
+//
+// Typical uses are `og:image`s for blog posts or snapshots of charts.
+//
+// Parameters:
+//   - `aTemplate`: The template to execute.
+//   - `aData`: The data to pass to `aTemplate`.
+//   - `aWidth`: The viewport's width; `0` uses the [ImageWidth] value.
+//   - `aHeight`: The viewport's height; `0` uses the [ImageHeight] value.
+//
+// Returns:
+//   - `string`: The file name of the saved image.
+//   - `error`: A possible error during creation of the image.
+func CreateImageFromTemplate(aTemplate *template.Template, aData any, aWidth, aHeight int) (string, error) {
+	if nil == aTemplate {
+		return "", errors.New(ssLibName + ": missing template")
+	}
+
+	var buffer bytes.Buffer
+	if err := aTemplate.Execute(&buffer, aData); nil != err {
+		return "", err
+	}
+
+	if 0 >= aWidth {
+		aWidth = ssOptions.ImageWidth
+	}
+	if 0 >= aHeight {
+		aHeight = ssOptions.ImageHeight
+	}
+	name := templateName(aTemplate.Name(), buffer.Bytes(), aWidth, aHeight)
+
+	// Use the requested viewport for this image only:
+	options := *ssOptions
+	options.ImageWidth, options.ImageHeight = aWidth, aHeight
+
+	return createImageFromHTML(&options, buffer.String(), name)
+} // CreateImageFromTemplate()
+
+/* _EoF_ */
@@ -0,0 +1,73 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"testing"
+)
+
+func Test_htmlName(t *testing.T) {
+	ext := "." + ImageType()
+
+	tests := []struct {
+		name  string
+		aName string
+		want  string
+	}{
+		{"1", "", ""},
+		{"2", "  ", ""},
+		{"3", "post-42", "post-42" + ext},
+		{"4", "social.html", "social" + ext},
+		{"5", "../../etc/passwd", "passwd" + ext},
+		{"6", "/", ""},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlName(tt.aName); got != tt.want {
+				t.Errorf("%q: htmlName() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_htmlName()
+
+func Test_templateName(t *testing.T) {
+	h1 := []byte("<p>one</p>")
+	h2 := []byte("<p>two</p>")
+	w1 := templateName("t", h1, 640, 480)
+
+	tests := []struct {
+		name      string
+		aTemplate string
+		aHTML     []byte
+		aWidth    int
+		aHeight   int
+		wantSame  bool
+	}{
+		{"1", "t", h1, 640, 480, true},
+		{"2", "t", h2, 640, 480, false},
+		{"3", "u", h1, 640, 480, false},
+		{"4", "t", h1, 480, 640, false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := templateName(tt.aTemplate, tt.aHTML, tt.aWidth, tt.aHeight)
+			if 35 != len(got) {
+				t.Errorf("%q: templateName() = %q, want 35 characters",
+					tt.name, got)
+			}
+			if (got == w1) != tt.wantSame {
+				t.Errorf("%q: templateName() = %q, same as %q: %v",
+					tt.name, got, w1, tt.wantSame)
+			}
+		})
+	}
+} // Test_templateName()
+
+/* _EoF_ */
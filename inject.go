@@ -0,0 +1,207 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+The scripts file is a host rules file (see `hostrules.go`) with the
+actions
+
+	before SCRIPTFILE
+	after  SCRIPTFILE
+
+`before` scripts are run in each new document before the page's own
+scripts, `after` scripts are run once the page is loaded, right before
+the screenshot is taken.
+Relative script filenames are relative to the scripts file's directory.
+
+Example:
+
+	*            before  stub-notification.js
+	example.com  after   accept-cookies.js
+*/
+
+const (
+	// Rule keyword for scripts to run after loading a page:
+	actionAfter = `after`
+
+	// Rule keyword for scripts to run before a page's own scripts:
+	actionBefore = `before`
+
+	// Name of the JS variable collecting errors of `before` scripts:
+	scriptErrorsVar = `window.__screenshotErrors`
+)
+
+var (
+	// The rules of the scripts file:
+	ssScripts = tHostRules{
+		kind: RulesScripts,
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `loadScript()` reads the script file `aFilename` named in the
+// scripts file.
+//
+// Parameters:
+//   - `aFilename`: The (possibly relative) path/file name of the script.
+//
+// Returns:
+//   - `string`: The script's source code.
+//   - `error`: A possible error reading the script file.
+func loadScript(aFilename string) (string, error) {
+	data, err := os.ReadFile(relPath(ssScripts.file(), aFilename)) // #nosec G304
+	if nil != err {
+		return "", err
+	}
+
+	return string(data), nil
+} // loadScript()
+
+// `scriptTasks()` returns the browser actions running the scripts
+// configured for the host of `aURL`.
+//
+// Errors of the scripts don't abort the capture but are reported
+// in `aCapture`.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//   - `aCapture`: The capture result to receive script errors.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform before navigation.
+//   - `chromedp.Tasks`: The actions to perform after loading the page.
+func scriptTasks(aURL string, aCapture *TCaptureResult) (rBefore, rAfter chromedp.Tasks) {
+	var afterScripts []string
+	host, port, path := urlParts(aURL)
+
+	for _, rule := range ssScripts.rules() {
+		if !rule.pattern.matches(host, port, path) {
+			continue
+		}
+		switch rule.action {
+		case actionBefore:
+			src, err := loadScript(rule.arg)
+			if nil != err {
+				aCapture.ScriptErrors = append(aCapture.ScriptErrors, err.Error())
+				continue
+			}
+			script := wrapScript(rule.arg, src)
+			rBefore = append(rBefore, chromedp.ActionFunc(func(aContext context.Context) error {
+				_, err := page.AddScriptToEvaluateOnNewDocument(script).Do(aContext)
+				return err
+			}))
+
+		case actionAfter:
+			afterScripts = append(afterScripts, rule.arg)
+		}
+	}
+
+	if 0 < len(rBefore) {
+		// Make sure a page's Content-Security-Policy doesn't
+		// prevent the (wrapped) scripts from running:
+		rBefore = append(chromedp.Tasks{page.SetBypassCSP(true)}, rBefore...)
+
+		// Collect the errors of the `before` scripts:
+		rAfter = append(rAfter, chromedp.ActionFunc(func(aContext context.Context) error {
+			var errs []string
+			if err := chromedp.Evaluate(scriptErrorsVar+` || []`, &errs).Do(aContext); nil != err {
+				aCapture.ScriptErrors = append(aCapture.ScriptErrors, err.Error())
+			}
+			aCapture.ScriptErrors = append(aCapture.ScriptErrors, errs...)
+
+			return nil
+		}))
+	}
+
+	for _, name := range afterScripts {
+		rAfter = append(rAfter, chromedp.ActionFunc(func(aContext context.Context) error {
+			src, err := loadScript(name)
+			if nil == err {
+				err = chromedp.Evaluate(src, nil).Do(aContext)
+			}
+			if nil != err {
+				aCapture.ScriptErrors = append(aCapture.ScriptErrors, name+": "+err.Error())
+			}
+
+			return nil // script errors don't abort the capture
+		}))
+	}
+
+	return
+} // scriptTasks()
+
+// `wrapScript()` wraps the source code `aSource` so that errors are
+// collected instead of being lost.
+//
+// Parameters:
+//   - `aName`: The script's name used in error messages.
+//   - `aSource`: The script's source code.
+//
+// Returns:
+//   - `string`: The wrapped script.
+func wrapScript(aName, aSource string) string {
+	// JSON encoding results in valid JavaScript string literals.
+	name, _ := json.Marshal(aName + ": ")
+	src, _ := json.Marshal(aSource)
+
+	return `try{(0,eval)(` + string(src) + `)}catch(e){(` +
+		scriptErrorsVar + `=` + scriptErrorsVar + `||[]).push(` +
+		string(name) + `+e)}`
+} // wrapScript()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `ScriptsFile()` returns the name of the file listing the scripts
+// to run during page processing.
+//
+// Returns:
+//   - `string`: The path/file name of the scripts file.
+func ScriptsFile() string {
+	return ssOptions.ScriptsFile
+} // ScriptsFile()
+
+// `SetScriptsFile()` configures the name of the file listing the
+// scripts to run during page processing.
+//
+// Each line of that file consists of a host/domain (or `*` for all
+// hosts), the keyword `before` or `after`, and the name of a script
+// file:
+//
+//	example.com  after   accept-cookies.js
+//	*            before  stub-notification.js
+//
+// `before` scripts are run in each new document before the page's own
+// scripts, `after` scripts once the page is loaded.
+// Errors of those scripts are reported by [Capture] in the
+// `ScriptErrors` field of its result.
+//
+// NOTE: The scripts run only if JavaScript is active for the respective
+// page (see [JavaScript], [AvoidJSfile] and [NeedJSfile]).
+// An invalid filename disables the feature.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the scripts file.
+func SetScriptsFile(aFilename string) {
+	ssOptions.ScriptsFile = ssScripts.setFile(aFilename)
+} // SetScriptsFile()
+
+/* _EoF_ */
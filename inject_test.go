@@ -0,0 +1,34 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"testing"
+)
+
+func Test_wrapScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		aName   string
+		aSource string
+		want    string
+	}{
+		{"1", "a.js", "", `try{(0,eval)("")}catch(e){(window.__screenshotErrors=window.__screenshotErrors||[]).push("a.js: "+e)}`},
+		{"2", "b.js", `alert("x")`, `try{(0,eval)("alert(\"x\")")}catch(e){(window.__screenshotErrors=window.__screenshotErrors||[]).push("b.js: "+e)}`},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapScript(tt.aName, tt.aSource); got != tt.want {
+				t.Errorf("%q: wrapScript() = %v,\nwant %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_wrapScript()
+
+/* _EoF_ */
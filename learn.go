@@ -0,0 +1,418 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+The learned hosts file has the same format as the Avoid/Need JavaScript
+lists; each learned host is preceded by a comment line with the time
+and the URL of the page it was learned from:
+
+	# 2025-03-01T12:34:56Z https://www.example.com/news
+	www.example.com
+*/
+
+const (
+	// Min. number of elements in the body of a page not needing JS:
+	learnMinElements = 8
+
+	// Min. number of visible characters of a page not needing JS:
+	learnMinText = 64
+)
+
+type (
+	// TLearnedHost describes a host found to need JavaScript
+	// (see [SetLearnJSfile]).
+	TLearnedHost struct {
+		// The (normalised) hostname.
+		Host string
+
+		// Time the host was learned.
+		Time time.Time
+
+		// The address of the page the host was learned from.
+		URL string
+	}
+
+	// `tLearnedHosts` caches the learned hosts file.
+	tLearnedHosts struct {
+		sync.Mutex
+
+		// Name of the file the list was read from:
+		filename string
+
+		// The compiled host patterns:
+		patterns []*tHostPattern
+	}
+)
+
+var (
+	// Returned (wrapped) if a page captured without JavaScript
+	// looks like it needs JavaScript:
+	errNeedsJS = errors.New(ssLibName + ": page needs JavaScript")
+
+	// The learned hosts:
+	ssLearned tLearnedHosts
+
+	// R/O RegEx matching "please enable JavaScript" messages:
+	ssNoJSRE = regexp.MustCompile(`(?i)(enable|activate|turn on|switch on|allow)\s+(your\s+)?javascript|javascript\s+(is\s+)?(required|disabled|needed|must be (enabled|activated|turned on))|(requires?|needs?)\s+javascript`)
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `learnJS()` returns whether to check pages captured without
+// JavaScript whether they need it.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aProfile`: The options overridden by the profiles (may be `nil`).
+//
+// Returns:
+//   - `bool`: Whether the learning mode is active.
+func learnJS(aOptions *TScreenshotParams, aProfile map[string]any) bool {
+	_, profileJS := aProfile["JavaScript"]
+
+	return (0 < len(ssOptions.HostsLearnJSfile)) &&
+		!aOptions.JavaScript && !profileJS
+} // learnJS()
+
+// `learnTasks()` returns the browser actions checking whether the
+// page of `aURL` (loaded without JavaScript) needs JavaScript.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//   - `aCapture`: The capture result providing the page's status.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform after loading the page.
+func learnTasks(aURL string, aCapture *TCaptureResult) chromedp.Tasks {
+	if host, _, _ := urlParts(aURL); 0 == len(host) {
+		return nil // e.g. a local file
+	}
+
+	return chromedp.Tasks{
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			if isErrorStatus(aCapture.Status) {
+				return nil // see `SetErrorPages()`
+			}
+			// The DOM domain works with JavaScript disabled:
+			root, err := dom.GetDocument().WithDepth(-1).Do(aContext)
+			if (nil != err) || !needsJS(root) {
+				return nil
+			}
+
+			return fmt.Errorf("%w: '%s'", errNeedsJS, aURL)
+		}),
+	}
+} // learnTasks()
+
+// `needsJS()` returns whether the document `aRoot` looks like it
+// needs JavaScript to show its content.
+//
+// That's the case if the page asks to enable JavaScript, if its body
+// is blank, or if it has very little text and either a tiny DOM or
+// `noscript` content.
+//
+// Parameters:
+//   - `aRoot`: The document's root node.
+//
+// Returns:
+//   - `bool`: Whether the page seems to need JavaScript.
+func needsJS(aRoot *cdp.Node) bool {
+	body := findNode(aRoot, "body")
+	if nil == body {
+		return false // not an HTML document
+	}
+
+	var (
+		elements   int
+		media      bool
+		text, noJS strings.Builder
+		walk       func(aNode *cdp.Node, aNoScript bool)
+	)
+	walk = func(aNode *cdp.Node, aNoScript bool) {
+		for _, child := range aNode.Children {
+			switch child.NodeType {
+			case cdp.NodeTypeText:
+				if aNoScript {
+					noJS.WriteString(child.NodeValue + " ")
+				} else {
+					text.WriteString(child.NodeValue + " ")
+				}
+
+			case cdp.NodeTypeElement:
+				switch strings.ToLower(child.LocalName) {
+				case "script", "style", "template":
+					continue
+
+				case "img", "picture", "svg", "video":
+					media = true
+					elements++
+
+				case "noscript":
+					walk(child, true)
+					continue
+
+				default:
+					elements++
+				}
+				walk(child, aNoScript)
+			}
+		}
+	}
+	walk(body, false)
+
+	if ssNoJSRE.MatchString(text.String() + " " + noJS.String()) {
+		return true
+	}
+	if media {
+		return false
+	}
+	words := strings.Fields(text.String())
+	if 0 == len(words) {
+		return true // blank body
+	}
+	visible := len(strings.Join(words, " "))
+
+	return (learnMinText > visible) &&
+		((learnMinElements > elements) || (0 < len(strings.TrimSpace(noJS.String()))))
+} // needsJS()
+
+// `findNode()` returns the first element named `aName` below `aNode`.
+//
+// Parameters:
+//   - `aNode`: The node to search.
+//   - `aName`: The (lowercase) element name to look for.
+//
+// Returns:
+//   - `*cdp.Node`: The element found or `nil`.
+func findNode(aNode *cdp.Node, aName string) *cdp.Node {
+	if nil == aNode {
+		return nil
+	}
+	for _, child := range aNode.Children {
+		if (cdp.NodeTypeElement == child.NodeType) && (aName == strings.ToLower(child.LocalName)) {
+			return child
+		}
+		if found := findNode(child, aName); nil != found {
+			return found
+		}
+	}
+
+	return nil
+} // findNode()
+
+// `readLearnedFile()` reads the learned hosts file `aFilename`.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to read.
+//
+// Returns:
+//   - `[]TLearnedHost`: The learned hosts.
+func readLearnedFile(aFilename string) (rList []TLearnedHost) {
+	data, err := os.ReadFile(aFilename) // #nosec G304
+	if nil != err {
+		return
+	}
+
+	var learned TLearnedHost
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); 0 == len(line) {
+			continue
+		}
+		if `#` == line[0:1] {
+			fields := strings.Fields(line[1:])
+			if 0 < len(fields) {
+				if t, err := time.Parse(time.RFC3339, fields[0]); nil == err {
+					learned.Time, learned.URL = t, ""
+					if 1 < len(fields) {
+						learned.URL = fields[1]
+					}
+				}
+			}
+			continue
+		}
+		learned.Host = line
+		rList = append(rList, learned)
+		learned = TLearnedHost{}
+	}
+
+	return
+} // readLearnedFile()
+
+// `learn()` records the host of `aURL` as needing JavaScript.
+//
+// Parameters:
+//   - `aURL`: The address of the page needing JavaScript.
+//
+// Returns:
+//   - `bool`: Whether the host is now known to need JavaScript.
+func (lh *tLearnedHosts) learn(aURL string) bool {
+	host, port, path := urlParts(aURL)
+	if 0 == len(host) {
+		return false
+	}
+
+	lh.Lock()
+	defer lh.Unlock()
+
+	lh.load()
+	for _, pattern := range lh.patterns {
+		if pattern.matches(host, port, path) {
+			return true // already known
+		}
+	}
+	pattern := newHostPattern(host)
+	if nil == pattern {
+		return false
+	}
+	lh.patterns = append(lh.patterns, pattern)
+	log.Printf("%s: learned that '%s' needs JavaScript", ssLibName, host)
+
+	file, err := os.OpenFile(lh.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fs.FileMode(0640)) // #nosec G304
+	if nil != err {
+		log.Println(ssLibName, err)
+		return true // keep it in memory at least
+	}
+	defer file.Close()
+	if _, err = fmt.Fprintf(file, "# %s %s\n%s\n",
+		time.Now().UTC().Format(time.RFC3339), aURL, host); nil != err {
+		log.Println(ssLibName, err)
+	}
+
+	return true
+} // learn()
+
+// `load()` reads the learned hosts file if that didn't happen yet.
+//
+// NOTE: The caller has to hold the list's lock.
+func (lh *tLearnedHosts) load() {
+	if lh.filename == ssOptions.HostsLearnJSfile {
+		return
+	}
+	lh.filename, lh.patterns = ssOptions.HostsLearnJSfile, nil
+	for _, learned := range readLearnedFile(lh.filename) {
+		if pattern := newHostPattern(learned.Host); nil != pattern {
+			lh.patterns = append(lh.patterns, pattern)
+		}
+	}
+} // load()
+
+// `matches()` returns whether the host of `aURL` was learned to
+// need JavaScript.
+//
+// Parameters:
+//   - `aURL`: The URL to check.
+//
+// Returns:
+//   - `bool`: Whether the host needs JavaScript.
+func (lh *tLearnedHosts) matches(aURL string) bool {
+	if 0 == len(ssOptions.HostsLearnJSfile) {
+		return false
+	}
+	host, port, path := urlParts(aURL)
+	if 0 == len(host) {
+		return false
+	}
+
+	lh.Lock()
+	defer lh.Unlock()
+
+	lh.load()
+	for _, pattern := range lh.patterns {
+		if pattern.matches(host, port, path) {
+			return true
+		}
+	}
+
+	return false
+} // matches()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `LearnJSfile()` returns the name of the file recording the hosts
+// learned to need JavaScript.
+//
+// Returns:
+//   - `string`: The path/filename of the learned hosts.
+func LearnJSfile() string {
+	return ssOptions.HostsLearnJSfile
+} // LearnJSfile()
+
+// `SetLearnJSfile()` configures the name of the file recording the
+// hosts learned to need JavaScript, and thus activates the learning
+// mode.
+//
+// In that mode each page captured without JavaScript (i.e. with the
+// [JavaScript] option set `false`) is checked whether it asks to enable
+// JavaScript, has a blank body, or shows very little content. If so,
+// its host is appended to the file (using the format of the Avoid/Need
+// JavaScript lists) and the page is captured again with JavaScript
+// enabled; the capture's result reports this in its `LearnedJS` field.
+// Later captures of such hosts use JavaScript right away.
+// See [LearnedJSHosts] to review the learned hosts, e.g. to promote
+// them to the [NeedJSfile] list.
+//
+// The file is created if it doesn't exist yet. An empty filename (the
+// default) or a non-existing directory disables the learning mode.
+//
+// Parameters:
+//   - `aFilename`: The path/filename of the learned hosts.
+func SetLearnJSfile(aFilename string) {
+	ssOptions.HostsLearnJSfile = ""
+	if aFilename = strings.TrimSpace(aFilename); 0 == len(aFilename) {
+		return
+	}
+	filename, err := filepath.Abs(aFilename)
+	if nil != err {
+		return
+	}
+	if fi, err := os.Stat(filepath.Dir(filename)); (nil != err) || !fi.IsDir() {
+		return
+	}
+	ssOptions.HostsLearnJSfile = filename
+} // SetLearnJSfile()
+
+// `LearnedJSHosts()` returns the hosts learned to need JavaScript
+// (see [SetLearnJSfile]).
+//
+// Returns:
+//   - `[]TLearnedHost`: The learned hosts in the order they were learned.
+func LearnedJSHosts() []TLearnedHost {
+	if 0 == len(ssOptions.HostsLearnJSfile) {
+		return nil
+	}
+
+	ssLearned.Lock()
+	defer ssLearned.Unlock()
+
+	return readLearnedFile(ssOptions.HostsLearnJSfile)
+} // LearnedJSHosts()
+
+/* _EoF_ */
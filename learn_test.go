@@ -0,0 +1,139 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/cdp"
+)
+
+// `el()` returns an element node named `aName` with `aChildren`.
+func el(aName string, aChildren ...*cdp.Node) *cdp.Node {
+	return &cdp.Node{
+		Children:  aChildren,
+		LocalName: aName,
+		NodeName:  strings.ToUpper(aName),
+		NodeType:  cdp.NodeTypeElement,
+	}
+} // el()
+
+// `txt()` returns a text node with `aText`.
+func txt(aText string) *cdp.Node {
+	return &cdp.Node{
+		NodeName:  "#text",
+		NodeType:  cdp.NodeTypeText,
+		NodeValue: aText,
+	}
+} // txt()
+
+// `doc()` returns a document with `aBody` as the body's children.
+func doc(aBody ...*cdp.Node) *cdp.Node {
+	return &cdp.Node{
+		Children: []*cdp.Node{
+			el("html", el("head", el("title", txt("Test"))), el("body", aBody...)),
+		},
+		NodeName: "#document",
+		NodeType: cdp.NodeTypeDocument,
+	}
+} // doc()
+
+func Test_needsJS(t *testing.T) {
+	article := txt(strings.Repeat("Some article text to read. ", 10))
+	tests := []struct {
+		name  string
+		aRoot *cdp.Node
+		want  bool
+	}{
+		{"1", nil, false},
+		{"2", &cdp.Node{NodeType: cdp.NodeTypeDocument}, false},
+		{"3", doc(), true},
+		{"4", doc(txt("  \n\t ")), true},
+		{"5", doc(el("div", el("script", txt("render();")))), true},
+		{"6", doc(el("p", txt("Please enable JavaScript to continue."))), true},
+		{"7", doc(el("noscript", txt("You need to enable JavaScript to run this app."))), true},
+		{"8", doc(el("div", txt("Loading …")), el("noscript", txt("Sorry."))), true},
+		{"9", doc(el("p", txt("Hello world"))), true},
+		{"10", doc(el("main", el("article", el("p", article)))), false},
+		{"11", doc(el("div", el("img"))), false},
+		{"12", doc(el("main", el("p", article)), el("noscript", el("img"))), false},
+		{"13", doc(el("p", txt("JavaScript is required"))), true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsJS(tt.aRoot); got != tt.want {
+				t.Errorf("%q: needsJS() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_needsJS()
+
+func Test_tLearnedHosts_learn(t *testing.T) {
+	defer func(aLearn string, aJS bool) {
+		ssOptions.HostsLearnJSfile, ssOptions.JavaScript = aLearn, aJS
+		ssLearned = tLearnedHosts{}
+	}(ssOptions.HostsLearnJSfile, ssOptions.JavaScript)
+	fName := filepath.Join(t.TempDir(), "learned.list")
+	ssOptions.JavaScript = false
+	SetLearnJSfile(fName)
+	if LearnJSfile() != fName {
+		t.Fatalf("SetLearnJSfile() = %q, want %q", LearnJSfile(), fName)
+	}
+
+	tests := []struct {
+		name string
+		aURL string
+		want bool
+	}{
+		{"1", "", false},
+		{"2", "file:///tmp/index.html", false},
+		{"3", "https://www.Example.COM/app", true},
+		{"4", "https://www.example.com/other", true},
+		{"5", "https://example.org/", true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ssLearned.learn(tt.aURL); got != tt.want {
+				t.Errorf("%q: tLearnedHosts.learn() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	got := LearnedJSHosts()
+	if 2 != len(got) {
+		t.Fatalf("LearnedJSHosts() = %v, want 2 hosts", got)
+	}
+	if ("www.example.com" != got[0].Host) || ("https://www.Example.COM/app" != got[0].URL) || got[0].Time.IsZero() {
+		t.Errorf("LearnedJSHosts()[0] = %v", got[0])
+	}
+
+	// Re-read the file:
+	ssLearned = tLearnedHosts{}
+	if !useJavaScript(ssOptions, "https://www.example.com/", nil) {
+		t.Error("useJavaScript() = false, want true")
+	}
+	if useJavaScript(ssOptions, "https://example.com/", nil) {
+		t.Error("useJavaScript() = true, want false")
+	}
+
+	SetLearnJSfile(filepath.Join(fName, "nonexisting", "file"))
+	if 0 < len(LearnJSfile()) {
+		t.Errorf("SetLearnJSfile() = %q, want ''", LearnJSfile())
+	}
+	if _, err := os.Stat(fName); nil != err {
+		t.Error(err)
+	}
+} // Test_tLearnedHosts_learn()
+
+/* _EoF_ */
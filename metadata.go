@@ -0,0 +1,266 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"image"
+	_ "image/gif" // register GIF decoder
+	"io"
+	"net/url"
+	"strings"
+
+	_ "golang.org/x/image/webp" // register WebP decoder
+	"golang.org/x/net/html"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tPageMeta` holds the metadata of a web page which is
+	// relevant for generating a link preview.
+	tPageMeta struct {
+		// The page's description (`og:description` or `description`).
+		Description string
+
+		// Absolute URL of the page's favicon.
+		Icon string
+
+		// Absolute URL of the page's preview image (`og:image`).
+		Image string
+
+		// The page's title (`og:title` or `<title>`).
+		Title string
+	}
+)
+
+const (
+	// Max. number of bytes to read when looking for a page's metadata.
+	maxMetaSize = 1 << 20 // 1 MB
+
+	// Max. number of bytes to read when downloading a preview image.
+	maxImageSize = 16 << 20 // 16 MB
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `absURL()` resolves `aRef` relative to `aBase`.
+//
+// Parameters:
+//   - `aBase`: The URL of the page containing `aRef`.
+//   - `aRef`: The (possibly relative) reference to resolve.
+//
+// Returns:
+//   - `string`: The absolute URL or an empty string in case of errors.
+func absURL(aBase *url.URL, aRef string) string {
+	if aRef = strings.TrimSpace(aRef); 0 == len(aRef) {
+		return ""
+	}
+	ref, err := url.Parse(aRef)
+	if nil != err {
+		return ""
+	}
+	if nil == aBase {
+		return ref.String()
+	}
+
+	return aBase.ResolveReference(ref).String()
+} // absURL()
+
+// `attribute()` returns the value of the attribute `aName` of `aToken`.
+//
+// Parameters:
+//   - `aToken`: The HTML token to inspect.
+//   - `aName`: The (lowercase) name of the attribute.
+//
+// Returns:
+//   - `string`: The attribute's value or an empty string.
+func attribute(aToken html.Token, aName string) string {
+	for _, attr := range aToken.Attr {
+		if aName == attr.Key {
+			return attr.Val
+		}
+	}
+
+	return ""
+} // attribute()
+
+// `downloadImage()` retrieves the image addressed by `aURL` and
+// returns it decoded.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the image to download.
+//
+// Returns:
+//   - `image.Image`: The decoded image.
+//   - `error`: A possible processing error.
+func downloadImage(aContext context.Context, aOptions *TScreenshotParams, aURL string) (image.Image, error) {
+	response, err := httpGet(aContext, aOptions, aURL)
+	if nil != err {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	img, _, err := image.Decode(io.LimitReader(response.Body, maxImageSize))
+	if nil != err {
+		return nil, errors.New(ssLibName + ": can't decode image '" +
+			aURL + "': " + err.Error())
+	}
+
+	return img, nil
+} // downloadImage()
+
+// `fetchMetadata()` retrieves the web page addressed by `aURL` and
+// extracts the metadata relevant for a link preview.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `*tPageMeta`: The page's metadata.
+//   - `error`: A possible processing error.
+func fetchMetadata(aContext context.Context, aOptions *TScreenshotParams, aURL string) (*tPageMeta, error) {
+	response, err := httpGet(aContext, aOptions, aURL)
+	if nil != err {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// Use the final URL (after possible redirects) as base:
+	return readMetadata(response.Request.URL,
+		io.LimitReader(response.Body, maxMetaSize)), nil
+} // fetchMetadata()
+
+// `ogImage()` retrieves the `og:image` of the web page addressed
+// by `aURL` and returns it adjusted to the configured size and
+// encoded in the configured [ImageType].
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `[]byte`: The properly encoded image data.
+//   - `error`: A possible processing error.
+func ogImage(aContext context.Context, aOptions *TScreenshotParams, aURL string) ([]byte, error) {
+	meta, err := fetchMetadata(aContext, aOptions, aURL)
+	if nil != err {
+		return nil, err
+	}
+	if 0 == len(meta.Image) {
+		return nil, errors.New(ssLibName + ": no 'og:image' found for '" +
+			aURL + "'")
+	}
+
+	img, err := downloadImage(aContext, aOptions, meta.Image)
+	if nil != err {
+		return nil, err
+	}
+
+	return encodeImage(aOptions, cropScale(aOptions, img)), nil
+} // ogImage()
+
+// `readMetadata()` parses the HTML document provided by `aReader`
+// and returns the metadata relevant for a link preview.
+//
+// Parameters:
+//   - `aBase`: The document's URL used to resolve relative references.
+//   - `aReader`: The source of the HTML document to parse.
+//
+// Returns:
+//   - `*tPageMeta`: The document's metadata.
+func readMetadata(aBase *url.URL, aReader io.Reader) *tPageMeta {
+	var (
+		description, imgURL, title string
+		inTitle                    bool
+	)
+	result := &tPageMeta{}
+	tokenizer := html.NewTokenizer(aReader)
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// `io.EOF` or some real error: we're done.
+			goto done
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				// Metadata is expected in the document's head.
+				goto done
+
+			case "link":
+				rel := strings.ToLower(attribute(token, "rel"))
+				if (0 == len(result.Icon)) &&
+					(("icon" == rel) || ("shortcut icon" == rel)) {
+					result.Icon = absURL(aBase, attribute(token, "href"))
+				}
+
+			case "meta":
+				content := strings.TrimSpace(attribute(token, "content"))
+				property := attribute(token, "property")
+				if 0 == len(property) {
+					property = attribute(token, "name")
+				}
+				switch strings.ToLower(property) {
+				case "description":
+					description = content
+				case "og:description":
+					result.Description = content
+				case "og:image", "og:image:url", "og:image:secure_url":
+					if 0 == len(result.Image) {
+						result.Image = absURL(aBase, content)
+					}
+				case "twitter:image":
+					imgURL = absURL(aBase, content)
+				case "og:title":
+					result.Title = content
+				}
+
+			case "title":
+				inTitle = true
+			}
+
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); "title" == string(name) {
+				inTitle = false
+			}
+		}
+	}
+
+done:
+	if 0 == len(result.Description) {
+		result.Description = description
+	}
+	if 0 == len(result.Image) {
+		result.Image = imgURL
+	}
+	if 0 == len(result.Title) {
+		result.Title = strings.TrimSpace(title)
+	}
+	if (0 == len(result.Icon)) && (nil != aBase) {
+		result.Icon = absURL(aBase, "/favicon.ico")
+	}
+
+	return result
+} // readMetadata()
+
+/* _EoF_ */
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_absURL(t *testing.T) {
+	base, _ := url.Parse("https://example.com/news/article.html")
+
+	tests := []struct {
+		name  string
+		aBase *url.URL
+		aRef  string
+		want  string
+	}{
+		{"1", base, "", ""},
+		{"2", base, "/img/a.png", "https://example.com/img/a.png"},
+		{"3", base, "b.png", "https://example.com/news/b.png"},
+		{"4", base, "https://cdn.example.org/c.jpg", "https://cdn.example.org/c.jpg"},
+		{"5", nil, "d.png", "d.png"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := absURL(tt.aBase, tt.aRef); got != tt.want {
+				t.Errorf("%q: absURL() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_absURL()
+
+func Test_readMetadata(t *testing.T) {
+	base, _ := url.Parse("https://example.com/news/")
+
+	d1 := ``
+	w1 := &tPageMeta{Icon: "https://example.com/favicon.ico"}
+	//
+	d2 := `<!DOCTYPE html><html><head>
+<title> A Title </title>
+<meta name="description" content="A description">
+<link rel="icon" href="/icon.png">
+</head><body><meta property="og:image" content="ignored.png"></body></html>`
+	w2 := &tPageMeta{
+		Description: "A description",
+		Icon:        "https://example.com/icon.png",
+		Title:       "A Title",
+	}
+	//
+	d3 := `<html><head>
+<meta name="twitter:image" content="tw.png">
+<meta property="og:image" content="og.png">
+<meta property="og:title" content="OG Title">
+<meta property="og:description" content="OG description">
+<meta name="description" content="A description">
+<title>A Title</title>
+</head></html>`
+	w3 := &tPageMeta{
+		Description: "OG description",
+		Icon:        "https://example.com/favicon.ico",
+		Image:       "https://example.com/news/og.png",
+		Title:       "OG Title",
+	}
+	//
+	d4 := `<html><head><meta name="twitter:image" content="/tw.png"></head></html>`
+	w4 := &tPageMeta{
+		Icon:  "https://example.com/favicon.ico",
+		Image: "https://example.com/tw.png",
+	}
+
+	tests := []struct {
+		name  string
+		aData string
+		want  *tPageMeta
+	}{
+		{"1", d1, w1},
+		{"2", d2, w2},
+		{"3", d3, w3},
+		{"4", d4, w4},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readMetadata(base, strings.NewReader(tt.aData)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q: readMetadata() = %v,\nwant %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_readMetadata()
+
+/* _EoF_ */
@@ -0,0 +1,448 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+A profiles file is a JSON list of capture profiles like
+
+	[
+		{
+			"hosts": ["example.com", "=m.example.org"],
+			"options": {
+				"JavaScript": true,
+				"Mobile": true,
+				"ImageWidth": 412,
+				"WaitSelector": "main article"
+			}
+		}
+	]
+
+`hosts` are host patterns (see `hostpattern.go`), `options` are fields
+of `TScreenshotParams` (matched case-insensitively) overriding the
+global settings for the pages of the matching hosts; the options listed
+in `ssGlobalOptions` can't be overridden.
+If several profiles match a page their options are merged in the order
+of the file, i.e. later profiles win.
+*/
+
+type (
+	// `tProfile` is a single entry of a profiles file.
+	tProfile struct {
+		// The compiled host patterns the profile applies to.
+		hosts []*tHostPattern
+
+		// The options to override (by `TScreenshotParams` field name).
+		options map[string]json.RawMessage
+	}
+
+	// `tProfiles` caches the profiles read from a profiles file.
+	tProfiles struct {
+		sync.Mutex
+
+		// Name of the file the profiles were read from:
+		filename string
+
+		// List of profiles to test against:
+		list []tProfile
+
+		// Time of next reading the profiles file:
+		nextTime time.Time
+	}
+)
+
+var (
+	// The options configuring the browsers, caches and rule files
+	// shared by all captures which hence can't be set by a profile:
+	ssGlobalOptions = []string{
+		"BlockFile", "BrowserMaxRSS", "BrowserRecycle", "BrowserReuse",
+		"CanonicalParams", "CanonicalScheme", "ChromeFlags",
+		"ConsentFile", "CookieFile", "CredentialsFile", "ExecPath",
+		"Headless", "HostsAvoidJSfile", "HostsLearnJSfile",
+		"HostsNeedJSfile", "ImageDir", "ProfileDir", "ProfileMode",
+		"ProfilesFile", "Proxy", "ProxyBypass", "ProxyFile",
+		"RemoteURL", "ScriptsFile", "StylesFile",
+	}
+
+	// The profiles of the profiles file:
+	ssProfiles tProfiles
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `captureOptions()` returns the options to use for processing the
+// web page `aURL`: a copy of the current options with the options of
+// the profiles matching `aURL` applied.
+//
+// Only the fields set by the profiles are changed in the copy while
+// the global options remain untouched, so captures with different
+// profiles may run concurrently.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `*TScreenshotParams`: The options to use for `aURL`.
+//   - `map[string]any`: The values of the overridden options.
+func captureOptions(aURL string) (*TScreenshotParams, map[string]any) {
+	result := *ssOptions
+	options := ssProfiles.merged(aURL)
+	if 0 == len(options) {
+		return &result, nil
+	}
+
+	// Unmarshaling sets just the fields present in `data`:
+	data, _ := json.Marshal(options)
+	if err := json.Unmarshal(data, &result); nil != err {
+		log.Println(ssLibName, err)
+		result = *ssOptions
+		return &result, nil
+	}
+	for name := range options {
+		checkOption(&result, name)
+	}
+
+	profile := make(map[string]any, len(options))
+	fields := reflect.ValueOf(&result).Elem()
+	for name := range options {
+		profile[name] = fields.FieldByName(name).Interface()
+	}
+
+	return &result, profile
+} // captureOptions()
+
+// `checkOption()` applies the checks of the respective setter function
+// (like [SetImageQuality] or [SetMaxProcessTime]) to the option named
+// `aName` of `aOptions`.
+//
+// Parameters:
+//   - `aOptions`: The options to check.
+//   - `aName`: The name of the option set by a profile.
+func checkOption(aOptions *TScreenshotParams, aName string) {
+	switch aName {
+	case "AcceptLanguage":
+		aOptions.AcceptLanguage = strings.TrimSpace(aOptions.AcceptLanguage)
+
+	case "BlankRatio":
+		aOptions.BlankRatio = min(max(aOptions.BlankRatio, 0), 100)
+
+	case "BlankTolerance":
+		aOptions.BlankTolerance = min(max(aOptions.BlankTolerance, 0), 255)
+
+	case "ErrorPages":
+		if (ErrorPageFail > aOptions.ErrorPages) || (ErrorPageTTL < aOptions.ErrorPages) {
+			aOptions.ErrorPages = ErrorPageFail
+		}
+
+	case "ErrorTTL":
+		aOptions.ErrorTTL = max(aOptions.ErrorTTL, 1)
+
+	case "HideSelectors":
+		aOptions.HideSelectors = strings.TrimSpace(aOptions.HideSelectors)
+
+	case "ImageAge":
+		aOptions.ImageAge = max(aOptions.ImageAge, 0)
+
+	case "ImageHeight":
+		aOptions.ImageHeight = max(aOptions.ImageHeight, 0)
+
+	case "ImageQuality":
+		if (0 >= aOptions.ImageQuality) || (100 < aOptions.ImageQuality) {
+			aOptions.ImageQuality = 100 // i.e. 'png' format
+		}
+
+	case "ImageScale":
+		aOptions.ImageScale = max(aOptions.ImageScale, 0)
+
+	case "ImageWidth":
+		if 0 >= aOptions.ImageWidth {
+			aOptions.ImageWidth = defaultImageWidth
+		}
+
+	case "MaxProcessTime":
+		if 0 >= aOptions.MaxProcessTime {
+			aOptions.MaxProcessTime = 32
+		}
+
+	case "MimePolicy":
+		aOptions.MimePolicy = normaliseMimePolicy(aOptions.MimePolicy)
+
+	case "Platform":
+		if aOptions.Platform = strings.TrimSpace(aOptions.Platform); 0 == len(aOptions.Platform) {
+			aOptions.Platform = defaultPlatform
+		}
+
+	case "PreviewSource":
+		if (PreviewScreenshot > aOptions.PreviewSource) || (PreviewScreenshotFirst < aOptions.PreviewSource) {
+			aOptions.PreviewSource = PreviewScreenshot
+		}
+
+	case "RetryAttempts":
+		aOptions.RetryAttempts = max(aOptions.RetryAttempts, 1)
+
+	case "RetryDelay":
+		aOptions.RetryDelay = max(aOptions.RetryDelay, 0)
+
+	case "RetryOn":
+		aOptions.RetryOn = retryClasses(aOptions.RetryOn)
+
+	case "UserAgent":
+		if aOptions.UserAgent = strings.TrimSpace(aOptions.UserAgent); 0 == len(aOptions.UserAgent) {
+			aOptions.UserAgent = DefaultAgent
+		}
+
+	case "WaitSelector":
+		aOptions.WaitSelector = strings.TrimSpace(aOptions.WaitSelector)
+
+	case "WaitTime":
+		aOptions.WaitTime = max(aOptions.WaitTime, 0)
+	}
+} // checkOption()
+
+// `optionName()` returns the name of the `TScreenshotParams` field
+// matching `aKey` case-insensitively.
+//
+// Parameters:
+//   - `aKey`: The option name to look up.
+//
+// Returns:
+//   - `string`: The field's name or an empty string if there's none.
+func optionName(aKey string) string {
+	params := reflect.TypeOf(TScreenshotParams{})
+	for i := 0; i < params.NumField(); i++ {
+		if name := params.Field(i).Name; strings.EqualFold(name, aKey) {
+			return name
+		}
+	}
+
+	return ""
+} // optionName()
+
+// `readProfilesFile()` reads the named profiles file and returns
+// its profiles.
+//
+// Profiles without valid host patterns, unknown or global options
+// (see `ssGlobalOptions`) and options with a value of the wrong type
+// are skipped.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to read.
+//
+// Returns:
+//   - `[]tProfile`: The list of profiles read from `aFilename`.
+func readProfilesFile(aFilename string) (rList []tProfile) {
+	if 0 == len(aFilename) {
+		return
+	}
+
+	data, err := os.ReadFile(aFilename) // #nosec G304
+	if (nil != err) || (0 == len(bytes.TrimSpace(data))) {
+		return
+	}
+
+	var entries []struct {
+		Hosts   []string                   `json:"hosts"`
+		Options map[string]json.RawMessage `json:"options"`
+	}
+	if err = json.Unmarshal(data, &entries); nil != err {
+		log.Println(ssLibName, aFilename, err)
+		return
+	}
+
+	for _, entry := range entries {
+		profile := tProfile{
+			hosts:   hostPatterns(entry.Hosts),
+			options: make(map[string]json.RawMessage, len(entry.Options)),
+		}
+		for key, value := range entry.Options {
+			name := optionName(key)
+			if 0 == len(name) {
+				log.Printf("%s: %s: unknown option '%s'", ssLibName, aFilename, key)
+				continue
+			}
+			if slices.Contains(ssGlobalOptions, name) {
+				log.Printf("%s: %s: global option '%s' can't be set by a profile", ssLibName, aFilename, key)
+				continue
+			}
+			// Check the value's type before accepting it:
+			var params TScreenshotParams
+			field, _ := json.Marshal(map[string]json.RawMessage{name: value})
+			if err = json.Unmarshal(field, &params); nil != err {
+				log.Printf("%s: %s: option '%s': %v", ssLibName, aFilename, key, err)
+				continue
+			}
+			profile.options[name] = value
+		}
+		if (0 < len(profile.hosts)) && (0 < len(profile.options)) {
+			rList = append(rList, profile)
+		}
+	}
+
+	return
+} // readProfilesFile()
+
+// `useJavaScript()` returns whether to enable JavaScript for the page
+// of `aURL`.
+//
+// A profile setting the `JavaScript` option decides on its own;
+// otherwise the Avoid/Need JavaScript lists (see [THostRuleProvider])
+// and the learned hosts (see [SetLearnJSfile]) are consulted.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the web page to process.
+//   - `aProfile`: The options overridden by the profiles (may be `nil`).
+//
+// Returns:
+//   - `bool`: Whether JavaScript should be enabled.
+func useJavaScript(aOptions *TScreenshotParams, aURL string, aProfile map[string]any) bool {
+	if _, ok := aProfile["JavaScript"]; ok {
+		return aOptions.JavaScript
+	}
+	if aOptions.JavaScript {
+		// If the domain is found in the 'avoid' list then we
+		// do NOT want to activate JS here:
+		return !listed(aURL, RulesAvoidJS)
+	}
+
+	// If the domain is found in the 'need' list then we
+	// DO want to activate JS here:
+	return listed(aURL, RulesNeedJS) || ssLearned.matches(aURL)
+} // useJavaScript()
+
+// `merged()` returns the merged options of all profiles matching
+// `aURL`.
+//
+// Parameters:
+//   - `aURL`: The URL to check.
+//
+// Returns:
+//   - `map[string]json.RawMessage`: The options to override.
+func (pl *tProfiles) merged(aURL string) map[string]json.RawMessage {
+	host, port, path := urlParts(aURL)
+	if 0 == len(host) {
+		return nil
+	}
+
+	pl.Lock()
+	defer pl.Unlock()
+
+	var result map[string]json.RawMessage
+	for _, profile := range pl.profiles() {
+		for _, pattern := range profile.hosts {
+			if !pattern.matches(host, port, path) {
+				continue
+			}
+			if nil == result {
+				result = make(map[string]json.RawMessage)
+			}
+			for name, value := range profile.options {
+				result[name] = value
+			}
+			break
+		}
+	}
+
+	return result
+} // merged()
+
+// `profiles()` returns the current list of profiles, re-reading the
+// profiles file if [ReadWaitTime] has passed since it was last read.
+//
+// NOTE: The caller must hold the list's lock.
+//
+// Returns:
+//   - `[]tProfile`: The current list of profiles.
+func (pl *tProfiles) profiles() []tProfile {
+	if 0 == len(pl.filename) {
+		return nil
+	}
+
+	if (0 == len(pl.list)) || time.Now().After(pl.nextTime) {
+		if 0 < ssReadWaitTime {
+			pl.nextTime = time.Now().Add(time.Duration(ssReadWaitTime) * time.Minute)
+		}
+		pl.list = readProfilesFile(pl.filename)
+	}
+
+	return pl.list
+} // profiles()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `ProfilesFile()` returns the name of the file with the per-host
+// capture profiles.
+//
+// Returns:
+//   - `string`: The path/file name of the profiles file.
+func ProfilesFile() string {
+	return ssOptions.ProfilesFile
+} // ProfilesFile()
+
+// `SetProfilesFile()` configures the name of the JSON file with the
+// per-host capture profiles.
+//
+// Each profile consists of a list of host patterns and the options
+// (i.e. `TScreenshotParams` fields like `JavaScript`, `UserAgent`,
+// `Mobile`, `ImageWidth`, `WaitSelector`, or `HideSelectors`) to use
+// for the pages of the matching hosts:
+//
+//	[
+//		{
+//			"hosts": ["example.com"],
+//			"options": {"JavaScript": true, "WaitTime": 6000}
+//		}
+//	]
+//
+// If several profiles match a page their options are merged in the
+// order of the file. A profile setting `JavaScript` takes precedence
+// over the Avoid/Need JavaScript lists which are still used for all
+// other pages. The options applied to a page are reported in the
+// `Profile` field of its [TCaptureResult].
+// The file is re-read according to the [ReadWaitTime] setting.
+//
+// The options configuring the browsers, the image cache, and the rule
+// files shared by all captures (like `ImageDir`, `BrowserReuse`,
+// `ProfileDir`, `Proxy`, or `BlockFile`) can't be set by a profile.
+// The profiles' values are checked like those of the respective
+// setter functions, e.g. an `ImageQuality` above `100` selects the
+// PNG format as [SetImageQuality] does.
+// An invalid filename disables the feature.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the profiles file.
+func SetProfilesFile(aFilename string) {
+	var filename string
+	if aFilename = strings.TrimSpace(aFilename); 0 < len(aFilename) {
+		filename, _ = stat(aFilename)
+	}
+	// Keep the cached profiles while switching between them:
+	ssProfiles.Lock()
+	if filename != ssProfiles.filename {
+		ssProfiles.filename, ssProfiles.list = filename, nil
+	}
+	ssProfiles.Unlock()
+	ssOptions.ProfilesFile = filename
+} // SetProfilesFile()
+
+/* _EoF_ */
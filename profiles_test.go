@@ -0,0 +1,167 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+const testProfiles = `[
+	{
+		"hosts": ["example.com"],
+		"options": {"javascript": true, "UserAgent": "Profile/1.0", "ImageWidth": 412, "ImageDir": "/tmp"}
+	},
+	{
+		"hosts": ["=www.example.com"],
+		"options": {"ImageWidth": 640, "WaitTime": 6000, "Unknown": 1}
+	},
+	{
+		"hosts": ["example.org"],
+		"options": {"Mobile": "yes", "ProfilesFile": "other.json"}
+	},
+	{
+		"hosts": ["bad.example.net"],
+		"options": {"ImageHeight": -1, "ImageQuality": 150, "ImageWidth": -1, "MaxProcessTime": 0}
+	}
+]`
+
+func Test_readProfilesFile(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.profiles.json"
+	writeFile(fName, []byte(testProfiles), nil)
+	defer func() {
+		_ = os.Remove(fName)
+	}()
+
+	tests := []struct {
+		name      string
+		aFilename string
+		wantLen   int
+	}{
+		{"1", "", 0},
+		{"2", "./Crash_Test_Dummies.missing.json", 0},
+		{"3", fName, 3},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readProfilesFile(tt.aFilename); len(got) != tt.wantLen {
+				t.Errorf("%q: readProfilesFile() = %d profiles, want %d",
+					tt.name, len(got), tt.wantLen)
+			}
+		})
+	}
+} // Test_readProfilesFile()
+
+func Test_captureOptions(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.profiles.json"
+	writeFile(fName, []byte(testProfiles), nil)
+	defer func(aOptions TScreenshotParams) {
+		_ = os.Remove(fName)
+		aOptions.Do()
+	}(*ssOptions)
+	SetJavaScript(false)
+	SetImageWidth(896)
+	SetProfilesFile(fName)
+	before := *ssOptions
+
+	tests := []struct {
+		name      string
+		aURL      string
+		want      map[string]any
+		wantJS    bool
+		wantWidth int
+	}{
+		{"1", "https://example.org/", nil, false, 896},
+		{"2", "https://shop.example.com/", map[string]any{
+			"ImageWidth": 412,
+			"JavaScript": true,
+			"UserAgent":  "Profile/1.0",
+		}, true, 412},
+		{"3", "https://www.example.com/", map[string]any{
+			"ImageWidth": 640,
+			"JavaScript": true,
+			"UserAgent":  "Profile/1.0",
+			"WaitTime":   6000,
+		}, true, 640},
+		{"4", "https://bad.example.net/", map[string]any{
+			"ImageHeight":    0,
+			"ImageQuality":   100,
+			"ImageWidth":     defaultImageWidth,
+			"MaxProcessTime": 32,
+		}, false, defaultImageWidth},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, got := captureOptions(tt.aURL)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q: captureOptions() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if options.ImageWidth != tt.wantWidth {
+				t.Errorf("%q: captureOptions().ImageWidth = %d, want %d",
+					tt.name, options.ImageWidth, tt.wantWidth)
+			}
+			if options.ImageDir != before.ImageDir {
+				t.Errorf("%q: captureOptions().ImageDir = %q, want %q",
+					tt.name, options.ImageDir, before.ImageDir)
+			}
+			if js := useJavaScript(options, tt.aURL, got); js != tt.wantJS {
+				t.Errorf("%q: useJavaScript() = %v, want %v",
+					tt.name, js, tt.wantJS)
+			}
+			if *ssOptions != before {
+				t.Errorf("%q: global options changed", tt.name)
+			}
+		})
+	}
+} // Test_captureOptions()
+
+func Test_checkOption(t *testing.T) {
+	tests := []struct {
+		name   string
+		aName  string
+		aValue string
+		want   any
+	}{
+		{"1", "ImageQuality", `80`, 80},
+		{"2", "ImageQuality", `150`, 100},
+		{"3", "ImageQuality", `0`, 100},
+		{"4", "MaxProcessTime", `0`, 32},
+		{"5", "MaxProcessTime", `-5`, 32},
+		{"6", "ImageWidth", `-10`, defaultImageWidth},
+		{"7", "ImageHeight", `-10`, 0},
+		{"8", "ImageScale", `-1.5`, 0.0},
+		{"9", "BlankRatio", `120`, 100},
+		{"10", "ErrorPages", `9`, ErrorPageFail},
+		{"11", "PreviewSource", `-1`, PreviewScreenshot},
+		{"12", "RetryAttempts", `0`, 1},
+		{"13", "RetryOn", `"Browser, unknown,timeout"`, "browser,timeout"},
+		{"14", "UserAgent", `"  "`, DefaultAgent},
+		{"15", "WaitTime", `-100`, 0},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options TScreenshotParams
+			data, _ := json.Marshal(map[string]json.RawMessage{tt.aName: json.RawMessage(tt.aValue)})
+			if err := json.Unmarshal(data, &options); nil != err {
+				t.Fatalf("%q: json.Unmarshal() = %v", tt.name, err)
+			}
+			checkOption(&options, tt.aName)
+			if got := reflect.ValueOf(options).FieldByName(tt.aName).Interface(); got != tt.want {
+				t.Errorf("%q: checkOption() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_checkOption()
+
+/* _EoF_ */
@@ -0,0 +1,414 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Hosts where to avoid JavaScript (see [SetAvoidJSfile]).
+	RulesAvoidJS = `avoidjs`
+
+	// Requests to block (see [SetBlockFile]).
+	RulesBlock = `block`
+
+	// Cookie-consent rules (see [SetConsentFile]).
+	RulesConsent = `consent`
+
+	// Credentials for HTTP authentication (see [SetCredentialsFile]).
+	RulesCredentials = `credentials`
+
+	// Hosts requiring JavaScript (see [SetNeedJSfile]).
+	RulesNeedJS = `needjs`
+
+	// Proxies for certain hosts (see [SetProxyFile]).
+	RulesProxy = `proxy`
+
+	// Scripts to run during page processing (see [SetScriptsFile]).
+	RulesScripts = `scripts`
+
+	// Stylesheets and elements to hide (see [SetStylesFile]).
+	RulesStyles = `styles`
+
+	// Max. number of compiled host patterns to cache:
+	maxPatternCache = 1024
+)
+
+type (
+	// THostRule is a single per-host rule.
+	THostRule struct {
+		// The rule's keyword (like `hide` or `proxy`); not used
+		// for the [RulesAvoidJS] and [RulesNeedJS] lists.
+		Action string
+
+		// The rule's argument (like a CSS selector or a proxy URL).
+		Arg string
+
+		// The host pattern the rule applies to (e.g. `*`,
+		// `example.com` or `=www.example.com`).
+		Host string
+	}
+
+	// THostRuleProvider provides the per-host rules of the lists
+	// named by the `Rules…` constants (like [RulesAvoidJS] or
+	// [RulesStyles]).
+	//
+	// The default implementation (see [HostRuleProvider]) reads the
+	// rules from the respective files. Since the rules are requested
+	// for each capture an implementation should cache them.
+	THostRuleProvider interface {
+		// `HostRules()` returns the current rules of the list `aList`.
+		HostRules(aList string) []THostRule
+	}
+
+	// `tAddedRules` holds the rules added by [AddHostRule].
+	tAddedRules struct {
+		sync.Mutex
+
+		// The compiled rules by list name:
+		lists map[string][]tHostRule
+	}
+
+	// `tFileProvider` provides the rules read from the files.
+	tFileProvider struct{}
+
+	// `tPatternCache` caches the compiled patterns of the rules
+	// returned by a provider.
+	tPatternCache struct {
+		sync.Mutex
+
+		// The compiled patterns by pattern:
+		patterns map[string]*tHostPattern
+	}
+)
+
+var (
+	// The rules added by [AddHostRule]:
+	ssAddedRules tAddedRules
+
+	// The compiled patterns of the provider's rules:
+	ssPatterns tPatternCache
+
+	// The rule provider installed by [SetHostRuleProvider]
+	// (`nil` for the files):
+	ssProvider THostRuleProvider
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `compileRules()` returns the compiled version of `aList`, skipping
+// rules with invalid host patterns.
+//
+// Parameters:
+//   - `aList`: The rules to compile.
+//
+// Returns:
+//   - `[]tHostRule`: The compiled rules.
+func compileRules(aList []THostRule) (rList []tHostRule) {
+	for _, rule := range aList {
+		host := strings.TrimSpace(rule.Host)
+		if pattern := ssPatterns.get(host); nil != pattern {
+			rList = append(rList, tHostRule{
+				action:  strings.ToLower(strings.TrimSpace(rule.Action)),
+				arg:     strings.TrimSpace(rule.Arg),
+				host:    ruleHost(host),
+				pattern: pattern,
+			})
+		}
+	}
+
+	return
+} // compileRules()
+
+// `hostRules()` returns the file based rules of the list `aList`.
+//
+// Parameters:
+//   - `aList`: The name of the rules list.
+//
+// Returns:
+//   - `*tHostRules`: The rules or `nil` for an unknown list.
+func hostRules(aList string) *tHostRules {
+	switch aList {
+	case RulesBlock:
+		return &ssBlocking
+	case RulesConsent:
+		return &ssConsent
+	case RulesCredentials:
+		return &ssCredentials
+	case RulesProxy:
+		return &ssProxies
+	case RulesScripts:
+		return &ssScripts
+	case RulesStyles:
+		return &ssStyles
+	}
+
+	return nil
+} // hostRules()
+
+// `listed()` returns whether `aURL` is matched by the host list `aList`
+// (i.e. [RulesAvoidJS] or [RulesNeedJS]).
+//
+// Parameters:
+//   - `aURL`: The URL to check.
+//   - `aList`: The name of the host list.
+//
+// Returns:
+//   - `bool`: Whether `aURL` is matched by the list.
+func listed(aURL, aList string) bool {
+	host, port, path := urlParts(aURL)
+	if 0 == len(host) {
+		return false
+	}
+
+	if nil == ssProvider {
+		filename := ssOptions.HostsAvoidJSfile
+		if RulesNeedJS == aList {
+			filename = ssOptions.HostsNeedJSfile
+		}
+		if chk4(aURL, filename) {
+			return true
+		}
+	} else {
+		for _, rule := range compileRules(ssProvider.HostRules(aList)) {
+			if rule.pattern.matches(host, port, path) {
+				return true
+			}
+		}
+	}
+
+	for _, rule := range ssAddedRules.rules(aList) {
+		if rule.pattern.matches(host, port, path) {
+			return true
+		}
+	}
+
+	return false
+} // listed()
+
+// `validList()` returns whether `aList` names a rules list.
+//
+// Parameters:
+//   - `aList`: The name to check.
+//
+// Returns:
+//   - `bool`: Whether `aList` is a valid list name.
+func validList(aList string) bool {
+	return (RulesAvoidJS == aList) || (RulesNeedJS == aList) ||
+		(nil != hostRules(aList))
+} // validList()
+
+// `rules()` returns the rules added to the list `aList`.
+//
+// Parameters:
+//   - `aList`: The name of the rules list.
+//
+// Returns:
+//   - `[]tHostRule`: The added rules.
+func (ar *tAddedRules) rules(aList string) []tHostRule {
+	ar.Lock()
+	defer ar.Unlock()
+
+	return slices.Clone(ar.lists[aList])
+} // rules()
+
+// `HostRules()` returns the rules of the list `aList` as read from
+// the respective file.
+//
+// Parameters:
+//   - `aList`: The name of the rules list.
+//
+// Returns:
+//   - `[]THostRule`: The file's rules.
+func (tFileProvider) HostRules(aList string) (rList []THostRule) {
+	switch aList {
+	case RulesAvoidJS, RulesNeedJS:
+		hosts, filename := &ssAvoidJSsites, ssOptions.HostsAvoidJSfile
+		if RulesNeedJS == aList {
+			hosts, filename = &ssNeedJSsites, ssOptions.HostsNeedJSfile
+		}
+		if 0 == len(filename) {
+			return
+		}
+		if hosts.due(filename) {
+			hosts.reload()
+		}
+		hosts.Lock()
+		for _, line := range hosts.list {
+			rList = append(rList, THostRule{Host: line})
+		}
+		hosts.Unlock()
+
+	default:
+		if hr := hostRules(aList); nil != hr {
+			for _, rule := range hr.fileRules() {
+				rList = append(rList, THostRule{
+					Action: rule.action,
+					Arg:    rule.arg,
+					Host:   rule.host,
+				})
+			}
+		}
+	}
+
+	return
+} // HostRules()
+
+// `get()` returns the compiled `aPattern`.
+//
+// Parameters:
+//   - `aPattern`: The host pattern to compile.
+//
+// Returns:
+//   - `*tHostPattern`: The compiled pattern or `nil` if invalid.
+func (pc *tPatternCache) get(aPattern string) *tHostPattern {
+	pc.Lock()
+	defer pc.Unlock()
+
+	if pattern, ok := pc.patterns[aPattern]; ok {
+		return pattern
+	}
+	if (nil == pc.patterns) || (maxPatternCache <= len(pc.patterns)) {
+		pc.patterns = make(map[string]*tHostPattern)
+	}
+	pattern := newHostPattern(aPattern)
+	pc.patterns[aPattern] = pattern
+
+	return pattern
+} // get()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `AddHostRule()` adds `aRule` to the rules list `aList` (one of the
+// `Rules…` constants like [RulesAvoidJS] or [RulesStyles]).
+//
+// The added rules are kept in memory only and used in addition to
+// (and after) the rules of the installed [THostRuleProvider].
+// Adding an already existing rule does nothing.
+//
+// Parameters:
+//   - `aList`: The name of the rules list.
+//   - `aRule`: The rule to add.
+//
+// Returns:
+//   - `error`: An error for an unknown list, an invalid host pattern, or a missing action.
+func AddHostRule(aList string, aRule THostRule) error {
+	if !validList(aList) {
+		return errors.New(ssLibName + ": unknown rules list '" + aList + "'")
+	}
+	compiled := compileRules([]THostRule{aRule})
+	if 0 == len(compiled) {
+		return errors.New(ssLibName + ": invalid host pattern '" + aRule.Host + "'")
+	}
+	rule := compiled[0]
+	if (nil != hostRules(aList)) && (0 == len(rule.action)) {
+		return errors.New(ssLibName + ": missing action for host '" + aRule.Host + "'")
+	}
+
+	ssAddedRules.Lock()
+	defer ssAddedRules.Unlock()
+
+	if nil == ssAddedRules.lists {
+		ssAddedRules.lists = make(map[string][]tHostRule)
+	}
+	for _, r := range ssAddedRules.lists[aList] {
+		if (r.host == rule.host) && (r.action == rule.action) && (r.arg == rule.arg) {
+			return nil
+		}
+	}
+	ssAddedRules.lists[aList] = append(ssAddedRules.lists[aList], rule)
+
+	return nil
+} // AddHostRule()
+
+// `RemoveHostRule()` removes `aRule` from the rules added to the
+// rules list `aList` (see [AddHostRule]).
+//
+// Parameters:
+//   - `aList`: The name of the rules list.
+//   - `aRule`: The rule to remove.
+//
+// Returns:
+//   - `bool`: Whether the rule was found and removed.
+func RemoveHostRule(aList string, aRule THostRule) bool {
+	host := ruleHost(strings.TrimSpace(aRule.Host))
+	action := strings.ToLower(strings.TrimSpace(aRule.Action))
+	arg := strings.TrimSpace(aRule.Arg)
+
+	ssAddedRules.Lock()
+	defer ssAddedRules.Unlock()
+
+	for idx, r := range ssAddedRules.lists[aList] {
+		if (r.host == host) && (r.action == action) && (r.arg == arg) {
+			ssAddedRules.lists[aList] = slices.Delete(ssAddedRules.lists[aList], idx, idx+1)
+			return true
+		}
+	}
+
+	return false
+} // RemoveHostRule()
+
+// `HostRules()` returns the rules of the list `aList` (one of the
+// `Rules…` constants), i.e. the rules of the installed
+// [THostRuleProvider] followed by the rules added by [AddHostRule].
+//
+// Parameters:
+//   - `aList`: The name of the rules list.
+//
+// Returns:
+//   - `[]THostRule`: The current rules.
+func HostRules(aList string) []THostRule {
+	result := HostRuleProvider().HostRules(aList)
+	for _, rule := range ssAddedRules.rules(aList) {
+		result = append(result, THostRule{
+			Action: rule.action,
+			Arg:    rule.arg,
+			Host:   rule.host,
+		})
+	}
+
+	return result
+} // HostRules()
+
+// `HostRuleProvider()` returns the provider of the per-host rules.
+//
+// Returns:
+//   - `THostRuleProvider`: The installed or the file based provider.
+func HostRuleProvider() THostRuleProvider {
+	if nil == ssProvider {
+		return tFileProvider{}
+	}
+
+	return ssProvider
+} // HostRuleProvider()
+
+// `SetHostRuleProvider()` installs the provider of the per-host rules
+// (like the JavaScript host lists, or the blocking, consent, credentials,
+// proxy, scripts and styles rules) replacing the respective files.
+//
+// This allows to keep the rules e.g. in a database. A `nil` argument
+// restores the default provider reading the files.
+//
+// Parameters:
+//   - `aProvider`: The provider to use.
+func SetHostRuleProvider(aProvider THostRuleProvider) {
+	if _, ok := aProvider.(tFileProvider); ok {
+		aProvider = nil // use the files directly
+	}
+	ssProvider = aProvider
+} // SetHostRuleProvider()
+
+/* _EoF_ */
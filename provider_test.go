@@ -0,0 +1,164 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"reflect"
+	"testing"
+)
+
+// `tTestProvider` provides fixed rules for testing.
+type tTestProvider map[string][]THostRule
+
+func (tp tTestProvider) HostRules(aList string) []THostRule {
+	return tp[aList]
+} // HostRules()
+
+func TestAddHostRule(t *testing.T) {
+	defer func() {
+		ssAddedRules.lists = nil
+	}()
+
+	tests := []struct {
+		name    string
+		aList   string
+		aRule   THostRule
+		wantErr bool
+	}{
+		{"1", "unknown", THostRule{Host: "example.com"}, true},
+		{"2", RulesNeedJS, THostRule{Host: "re:(["}, true},
+		{"3", RulesStyles, THostRule{Host: "example.com"}, true},
+		{"4", RulesNeedJS, THostRule{Host: "Example.COM"}, false},
+		{"5", RulesNeedJS, THostRule{Host: "example.com"}, false},
+		{"6", RulesStyles, THostRule{Action: "hide", Arg: ".ad", Host: "*"}, false},
+		{"7", RulesAvoidJS, THostRule{Host: `re:^img\D`}, false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := AddHostRule(tt.aList, tt.aRule); (nil != err) != tt.wantErr {
+				t.Errorf("%q: AddHostRule() error = %v, wantErr %v",
+					tt.name, err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := len(ssAddedRules.rules(RulesNeedJS)); 1 != got {
+		t.Errorf("AddHostRule(): %d rules, want 1", got)
+	}
+	if !RemoveHostRule(RulesNeedJS, THostRule{Host: "EXAMPLE.com"}) {
+		t.Error("RemoveHostRule() = false, want true")
+	}
+	if RemoveHostRule(RulesNeedJS, THostRule{Host: "example.com"}) {
+		t.Error("RemoveHostRule() = true, want false")
+	}
+	if RemoveHostRule(RulesAvoidJS, THostRule{Host: `re:^IMG\D`}) {
+		t.Error("RemoveHostRule(re:) = true, want false")
+	}
+	if !RemoveHostRule(RulesAvoidJS, THostRule{Host: ` re:^img\D `}) {
+		t.Error("RemoveHostRule(re:) = false, want true")
+	}
+} // TestAddHostRule()
+
+func Test_listed(t *testing.T) {
+	defer func(aAvoid, aNeed string) {
+		SetHostRuleProvider(nil)
+		ssAddedRules.lists = nil
+		ssOptions.HostsAvoidJSfile, ssOptions.HostsNeedJSfile = aAvoid, aNeed
+	}(ssOptions.HostsAvoidJSfile, ssOptions.HostsNeedJSfile)
+	// No files at all:
+	ssOptions.HostsAvoidJSfile, ssOptions.HostsNeedJSfile = "", ""
+
+	SetHostRuleProvider(tTestProvider{
+		RulesAvoidJS: {{Host: ".example.org"}},
+	})
+	_ = AddHostRule(RulesNeedJS, THostRule{Host: "=www.example.com"})
+
+	tests := []struct {
+		name  string
+		aURL  string
+		aList string
+		want  bool
+	}{
+		{"1", "", RulesAvoidJS, false},
+		{"2", "https://www.example.org/", RulesAvoidJS, true},
+		{"3", "https://example.org/", RulesAvoidJS, false},
+		{"4", "https://www.example.com/", RulesNeedJS, true},
+		{"5", "https://example.com/", RulesNeedJS, false},
+		{"6", "https://www.example.com/", RulesAvoidJS, false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listed(tt.aURL, tt.aList); got != tt.want {
+				t.Errorf("%q: listed() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_listed()
+
+func Test_compileRules(t *testing.T) {
+	rules := compileRules([]THostRule{
+		{Action: "Hide", Arg: " .ad ", Host: " Example.COM "},
+		{Action: "hide", Arg: ".banner", Host: `re:^IMG\D`},
+		{Action: "hide", Arg: ".invalid", Host: `re:(`},
+	})
+	if 2 != len(rules) {
+		t.Fatalf("compileRules() = %d rules, want 2", len(rules))
+	}
+	if ("hide" != rules[0].action) || (".ad" != rules[0].arg) || ("example.com" != rules[0].host) {
+		t.Errorf("compileRules()[0] = %v", rules[0])
+	}
+	if `re:^IMG\D` != rules[1].host {
+		t.Errorf("compileRules()[1].host = %q, want %q", rules[1].host, `re:^IMG\D`)
+	}
+
+	tests := []struct {
+		name  string
+		aHost string
+		want  bool
+	}{
+		{"1", "img-a.example.com", true},
+		{"2", "img1.example.com", false},
+		{"3", "www.example.com", false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules[1].pattern.matches(tt.aHost, "", ""); got != tt.want {
+				t.Errorf("%q: compileRules() pattern matches = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_compileRules()
+
+func TestHostRules(t *testing.T) {
+	defer func() {
+		SetHostRuleProvider(nil)
+		ssAddedRules.lists = nil
+	}()
+
+	SetHostRuleProvider(tTestProvider{
+		RulesProxy: {{Action: "proxy", Arg: "direct", Host: "intranet.example.com"}},
+	})
+	_ = AddHostRule(RulesProxy, THostRule{Action: "Proxy", Arg: "socks5://127.0.0.1:1080", Host: "example.org"})
+
+	want := []THostRule{
+		{Action: "proxy", Arg: "direct", Host: "intranet.example.com"},
+		{Action: "proxy", Arg: "socks5://127.0.0.1:1080", Host: "example.org"},
+	}
+	if got := HostRules(RulesProxy); !reflect.DeepEqual(got, want) {
+		t.Errorf("HostRules() = %v,\nwant %v", got, want)
+	}
+	if got := hostProxy("https://www.example.org/"); "socks5://127.0.0.1:1080" != got {
+		t.Errorf("hostProxy() = %q, want %q", got, "socks5://127.0.0.1:1080")
+	}
+} // TestHostRules()
+
+/* _EoF_ */
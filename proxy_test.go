@@ -0,0 +1,85 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"net/url"
+	"os"
+	"testing"
+)
+
+func Test_bypassList(t *testing.T) {
+	defer SetProxyBypass("")
+
+	tests := []struct {
+		name  string
+		aList string
+		want  string
+	}{
+		{"1", "", ""},
+		{"2", "example.com", "example.com;*.example.com"},
+		{"3", ".example.com, *.example.org", "*.example.com;*.example.org"},
+		{"4", "localhost,10.0.0.0/8, example.net:8080", "localhost;10.0.0.0/8;example.net:8080"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetProxyBypass(tt.aList)
+			if got := bypassList(); got != tt.want {
+				t.Errorf("%q: bypassList() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_bypassList()
+
+func Test_proxyFor(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.proxies"
+	rules := `
+example.org	proxy	socks5://127.0.0.1:1080
+intranet.example.com	proxy	Direct
+`
+	writeFile(fName, []byte(rules), nil)
+	defer func() {
+		_ = os.Remove(fName)
+		SetProxy("")
+		SetProxyBypass("")
+		SetProxyFile("")
+	}()
+	SetProxy("http://user:pw@proxy.example.net:3128")
+	SetProxyBypass("internal.example.net")
+	SetProxyFile(fName)
+
+	tests := []struct {
+		name       string
+		aURL       string
+		want       string
+		wantServer string
+	}{
+		{"1", "https://example.com/", "http://user:pw@proxy.example.net:3128", "http://proxy.example.net:3128"},
+		{"2", "https://www.example.org/", "socks5://127.0.0.1:1080", "socks5://127.0.0.1:1080"},
+		{"3", "https://intranet.example.com/", "", ""},
+		{"4", "https://www.internal.example.net/", "", "http://proxy.example.net:3128"},
+		{"5", "http://localhost:8080/", "", "http://proxy.example.net:3128"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			URL, _ := url.Parse(tt.aURL)
+			if got := proxyFor(URL); got != tt.want {
+				t.Errorf("%q: proxyFor() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+			if got := proxyServer(tt.aURL); got != tt.wantServer {
+				t.Errorf("%q: proxyServer() = %q, want %q",
+					tt.name, got, tt.wantServer)
+			}
+		})
+	}
+} // Test_proxyFor()
+
+/* _EoF_ */
@@ -0,0 +1,309 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Time to wait for further changes of a file before re-reading it
+	// (editors tend to write a file in several steps):
+	reloadDelay = 250 * time.Millisecond
+)
+
+type (
+	// TReloadFunc is called whenever an Avoid/Need hosts file was
+	// re-read.
+	//
+	// A `nil` error means the list was changed, otherwise `aErr`
+	// describes why the file was rejected (in which case the last
+	// good list is kept).
+	TReloadFunc func(aFilename string, aErr error)
+
+	// `tListWatcher` watches the directories of the Avoid/Need
+	// hosts files for changes.
+	tListWatcher struct {
+		sync.Mutex
+
+		// The watched lists by their (absolute) filename:
+		lists map[string]*tAvoidNeedFile
+
+		// The pending reloads by filename:
+		timers map[string]*time.Timer
+
+		// The file system watcher (`nil` if not yet started):
+		watcher *fsnotify.Watcher
+	}
+)
+
+var (
+	// The optional callback notified about reloaded hosts files:
+	ssReloadFunc TReloadFunc
+
+	// The watcher of the Avoid/Need hosts files:
+	ssWatcher tListWatcher
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `due()` returns whether the hosts list has to be (re-)read from
+// `aFilename`.
+//
+// A new filename is registered with the file system watcher; the
+// polling according to [ReadWaitTime] is used only if the file can't
+// be watched or no list could be read yet.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the hosts list.
+//
+// Returns:
+//   - `bool`: Whether to read the file.
+func (an *tAvoidNeedFile) due(aFilename string) bool {
+	an.Lock()
+	defer an.Unlock()
+
+	if aFilename != an.filename {
+		an.filename, an.list, an.patterns = aFilename, nil, nil
+		an.watched = ssWatcher.add(aFilename, an)
+		an.nextTime = time.Now()
+	}
+	if an.watched && (0 < len(an.patterns)) {
+		return false // changes are reported by the watcher
+	}
+	if time.Now().Before(an.nextTime) {
+		return false
+	}
+	if 0 < ssReadWaitTime {
+		an.nextTime = time.Now().Add(time.Duration(ssReadWaitTime) * time.Minute)
+	}
+
+	return true
+} // due()
+
+// `load()` reads the hosts list's file and compiles its patterns.
+//
+// Invalid patterns are logged and skipped. If the file can't be read,
+// is empty or doesn't contain any valid pattern the last good list
+// is kept.
+//
+// NOTE: The caller has to hold the list's lock.
+//
+// Returns:
+//   - `bool`: Whether the list was changed.
+//   - `error`: The reason why the file was rejected.
+func (an *tAvoidNeedFile) load() (bool, error) {
+	fi, err := os.Stat(an.filename)
+	if nil != err {
+		return false, err
+	}
+	if 0 == fi.Size() {
+		return false, errors.New(ssLibName + ": '" + an.filename + "' is empty")
+	}
+
+	list := readListFile(an.filename)
+	patterns := make([]*tHostPattern, 0, len(list))
+	for _, line := range list {
+		if pattern := newHostPattern(line); nil != pattern {
+			patterns = append(patterns, pattern)
+		} else {
+			log.Printf("%s: %s: invalid host pattern '%s'", ssLibName, an.filename, line)
+		}
+	}
+	if 0 == len(patterns) {
+		return false, errors.New(ssLibName + ": '" + an.filename + "' contains no valid host pattern")
+	}
+	if slices.Equal(list, an.list) {
+		return false, nil
+	}
+	an.list, an.patterns = list, patterns
+
+	return true, nil
+} // load()
+
+// `matches()` returns whether the host list matches the given URL parts.
+//
+// Parameters:
+//   - `aHost`: The (lowercased) hostname to check.
+//   - `aPort`: The port to check.
+//   - `aPath`: The path to check.
+//
+// Returns:
+//   - `bool`: Whether a pattern of the list matches.
+func (an *tAvoidNeedFile) matches(aHost, aPort, aPath string) bool {
+	an.Lock()
+	defer an.Unlock()
+
+	for _, pattern := range an.patterns {
+		if pattern.matches(aHost, aPort, aPath) {
+			return true
+		}
+	}
+
+	return false
+} // matches()
+
+// `reload()` re-reads the hosts list's file and notifies the
+// [SetReloadFunc] callback about changes and errors.
+func (an *tAvoidNeedFile) reload() {
+	an.Lock()
+	filename := an.filename
+	changed, err := an.load()
+	an.Unlock()
+
+	if nil != err {
+		log.Println(ssLibName, err, "(keeping the last good list)")
+	}
+	if (changed || (nil != err)) && (nil != ssReloadFunc) {
+		ssReloadFunc(filename, err)
+	}
+} // reload()
+
+// `add()` starts watching `aFilename` for changes of `aList`.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the hosts list.
+//   - `aList`: The hosts list to reload on changes.
+//
+// Returns:
+//   - `bool`: Whether the file is watched.
+func (lw *tListWatcher) add(aFilename string, aList *tAvoidNeedFile) bool {
+	lw.Lock()
+	defer lw.Unlock()
+
+	if nil == lw.watcher {
+		watcher, err := fsnotify.NewWatcher()
+		if nil != err {
+			log.Println(ssLibName, err)
+			return false
+		}
+		lw.lists = make(map[string]*tAvoidNeedFile)
+		lw.timers = make(map[string]*time.Timer)
+		lw.watcher = watcher
+		go lw.run(watcher)
+	}
+
+	// Forget the list's previous file:
+	for name, list := range lw.lists {
+		if list == aList {
+			delete(lw.lists, name)
+			lw.unwatch(filepath.Dir(name))
+		}
+	}
+
+	filename, err := filepath.Abs(aFilename)
+	if nil != err {
+		return false
+	}
+	// Watching the directory catches editors replacing the file:
+	if err = lw.watcher.Add(filepath.Dir(filename)); nil != err {
+		log.Println(ssLibName, err)
+		return false
+	}
+	lw.lists[filename] = aList
+
+	return true
+} // add()
+
+// `changed()` schedules the reload of the list belonging to the
+// changed file `aFilename`.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the changed file.
+func (lw *tListWatcher) changed(aFilename string) {
+	lw.Lock()
+	defer lw.Unlock()
+
+	list, ok := lw.lists[aFilename]
+	if !ok {
+		return // some other file
+	}
+	if timer, ok := lw.timers[aFilename]; ok {
+		timer.Reset(reloadDelay)
+		return
+	}
+	lw.timers[aFilename] = time.AfterFunc(reloadDelay, func() {
+		lw.Lock()
+		delete(lw.timers, aFilename)
+		lw.Unlock()
+		list.reload()
+	})
+} // changed()
+
+// `run()` processes the events of `aWatcher` until it's closed.
+//
+// Parameters:
+//   - `aWatcher`: The file system watcher to listen to.
+func (lw *tListWatcher) run(aWatcher *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-aWatcher.Events:
+			if !ok {
+				return
+			}
+			if fsnotify.Chmod != event.Op {
+				lw.changed(filepath.Clean(event.Name))
+			}
+
+		case err, ok := <-aWatcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println(ssLibName, err)
+		}
+	}
+} // run()
+
+// `unwatch()` stops watching `aDirectory` unless it contains another
+// watched file.
+//
+// NOTE: The caller has to hold the watcher's lock.
+//
+// Parameters:
+//   - `aDirectory`: The directory to stop watching.
+func (lw *tListWatcher) unwatch(aDirectory string) {
+	for name := range lw.lists {
+		if filepath.Dir(name) == aDirectory {
+			return
+		}
+	}
+	_ = lw.watcher.Remove(aDirectory)
+} // unwatch()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `SetReloadFunc()` installs a callback notified whenever an Avoid/Need
+// hosts file was re-read (see [SetAvoidJSfile] and [SetNeedJSfile]).
+//
+// The hosts files are watched for changes and re-read as soon as
+// they're modified. If a modified file can't be read, is empty or
+// doesn't contain any valid host pattern the error is logged, the
+// last good list is kept, and the callback is called with that error.
+//
+// NOTE: The callback is called from a separate goroutine.
+// A `nil` argument removes the callback.
+//
+// Parameters:
+//   - `aFunc`: The callback to use.
+func SetReloadFunc(aFunc TReloadFunc) {
+	ssReloadFunc = aFunc
+} // SetReloadFunc()
+
+/* _EoF_ */
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_tAvoidNeedFile_load(t *testing.T) {
+	const fName = "./Crash_Test_Dummies.hosts.list"
+	defer func() {
+		_ = os.Remove(fName)
+	}()
+	hosts := &tAvoidNeedFile{filename: fName}
+
+	tests := []struct {
+		name        string
+		aContent    string
+		wantChanged bool
+		wantErr     bool
+		wantLen     int
+	}{
+		{"1", "", false, true, 0},
+		{"2", "example.com\n# comment\n.example.org\n", true, false, 2},
+		{"3", "example.com\n.example.org\n", false, false, 2},
+		{"4", "", false, true, 2},
+		{"5", "re:([\n", false, true, 2},
+		{"6", "example.net\nre:([\n", true, false, 1},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFile(fName, []byte(tt.aContent), nil)
+			changed, err := hosts.load()
+			if (changed != tt.wantChanged) || ((nil != err) != tt.wantErr) {
+				t.Errorf("%q: tAvoidNeedFile.load() = %v, %v, want %v, %v",
+					tt.name, changed, err, tt.wantChanged, tt.wantErr)
+			}
+			if len(hosts.patterns) != tt.wantLen {
+				t.Errorf("%q: tAvoidNeedFile.load() = %d patterns, want %d",
+					tt.name, len(hosts.patterns), tt.wantLen)
+			}
+		})
+	}
+} // Test_tAvoidNeedFile_load()
+
+func TestSetReloadFunc(t *testing.T) {
+	dir := t.TempDir()
+	fName := filepath.Join(dir, defaultHostsNeedJS)
+	_ = os.WriteFile(fName, []byte("example.com\n"), 0644)
+
+	reloaded := make(chan error, 8)
+	SetReloadFunc(func(aFilename string, aErr error) {
+		if fName == aFilename {
+			reloaded <- aErr
+		}
+	})
+	defer SetReloadFunc(nil)
+
+	wait := func() (error, bool) {
+		select {
+		case err := <-reloaded:
+			return err, true
+		case <-time.After(5 * time.Second):
+			return nil, false
+		}
+	}
+
+	if !chk4("https://www.example.com/", fName) {
+		t.Fatal("chk4() = false, want true")
+	}
+	if _, ok := wait(); !ok {
+		t.Fatal("SetReloadFunc(): no initial notification")
+	}
+
+	_ = os.WriteFile(fName, []byte("example.org\n"), 0644)
+	if err, ok := wait(); !ok || (nil != err) {
+		t.Fatalf("SetReloadFunc(): change = %v, %v", err, ok)
+	}
+	if chk4("https://www.example.com/", fName) || !chk4("https://example.org/", fName) {
+		t.Error("chk4(): list not reloaded")
+	}
+
+	_ = os.WriteFile(fName, nil, 0644)
+	if err, ok := wait(); !ok || (nil == err) {
+		t.Fatalf("SetReloadFunc(): empty file = %v, %v", err, ok)
+	}
+	if !chk4("https://example.org/", fName) {
+		t.Error("chk4(): last good list not kept")
+	}
+} // TestSetReloadFunc()
+
+/* _EoF_ */
@@ -0,0 +1,290 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+var (
+	// Additional HTTP headers to send with each request:
+	ssHeaders map[string]string
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `extraHeaders()` returns the additional HTTP headers to send
+// with each browser request.
+//
+// Returns:
+//   - `network.Headers`: The headers to use.
+func extraHeaders() network.Headers {
+	result := make(network.Headers, len(ssHeaders))
+	for key, value := range ssHeaders {
+		result[key] = value
+	}
+
+	return result
+} // extraHeaders()
+
+// `fetchTasks()` returns the browser actions intercepting the requests
+// of the web page `aURL` to block unwanted requests (see [SetBlockFile])
+// and to answer authentication challenges (see [SetCredentialsFile]
+// and [SetProxy]).
+//
+// The page's own document (including its redirects) is never blocked
+// while the documents of embedded frames are subject to the rules.
+// The number of blocked requests is reported in `aCapture`.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//   - `aCapture`: The capture result to receive the number of blocked requests.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform before navigation.
+//   - `chromedp.Tasks`: The actions to perform after loading the page.
+func fetchTasks(aURL string, aCapture *TCaptureResult) (rBefore, rAfter chromedp.Tasks) {
+	blocker := newBlocker(aURL)
+	proxyName, proxyPass := proxyUser(aURL)
+	doAuth := (nil != ssCredentialsFunc) || (0 < len(ssCredentials.rules())) ||
+		(0 < len(proxyName))
+	if (nil == blocker) && !doAuth {
+		return
+	}
+
+	var count atomic.Int32
+	// Requests whose authentication challenge was answered already:
+	answered := make(map[fetch.RequestID]bool)
+
+	rBefore = chromedp.Tasks{
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			// The main frame's ID equals the tab's target ID:
+			var mainFrame cdp.FrameID
+			if c := chromedp.FromContext(aContext); (nil != c) && (nil != c.Target) {
+				mainFrame = cdp.FrameID(c.Target.TargetID)
+			}
+
+			chromedp.ListenTarget(aContext, func(aEvent any) {
+				// Sending commands from within the event handler
+				// would block the event loop, hence the goroutines.
+				switch ev := aEvent.(type) {
+				case *fetch.EventRequestPaused:
+					go func() {
+						page := (network.ResourceTypeDocument == ev.ResourceType) &&
+							(mainFrame == ev.FrameID)
+						if (nil != blocker) && !page &&
+							blocker.blocks(ev.Request.URL, ev.ResourceType) {
+							count.Add(1)
+							_ = fetch.FailRequest(ev.RequestID, network.ErrorReasonBlockedByClient).Do(aContext)
+							return
+						}
+						_ = fetch.ContinueRequest(ev.RequestID).Do(aContext)
+					}()
+
+				case *fetch.EventAuthRequired:
+					// Don't try the same (wrong) credentials again:
+					retry := answered[ev.RequestID]
+					answered[ev.RequestID] = true
+					go func() {
+						response := &fetch.AuthChallengeResponse{
+							Response: fetch.AuthChallengeResponseResponseCancelAuth,
+						}
+						if !retry {
+							user, password := proxyName, proxyPass
+							if fetch.AuthChallengeSourceProxy != ev.AuthChallenge.Source {
+								user, password = credentials(hostOf(ev.Request.URL))
+							}
+							if 0 < len(user) {
+								response.Response = fetch.AuthChallengeResponseResponseProvideCredentials
+								response.Username, response.Password = user, password
+							}
+						}
+						_ = fetch.ContinueWithAuth(ev.RequestID, response).Do(aContext)
+					}()
+				}
+			})
+
+			return fetch.Enable().WithHandleAuthRequests(doAuth).Do(aContext)
+		}),
+	}
+
+	if nil != blocker {
+		rAfter = chromedp.Tasks{
+			chromedp.ActionFunc(func(aContext context.Context) error {
+				aCapture.BlockedRequests = int(count.Load())
+
+				return nil
+			}),
+		}
+	}
+
+	return
+} // fetchTasks()
+
+// `httpClient()` returns the HTTP client to use for direct downloads.
+//
+// Returns:
+//   - `*http.Client`: The configured HTTP client.
+func httpClient() *http.Client {
+	return &http.Client{
+		Jar:       httpJar(),
+		Transport: ssTransport,
+	}
+} // httpClient()
+
+// `httpDo()` sends a GET request for `aURL` using the configured
+// [UserAgent], [AcceptLanguage], [Headers], [CookieFile] and [Proxy].
+//
+// An authentication challenge of the server is answered with the
+// credentials configured for its host (see [SetCredentialsFile]).
+//
+// NOTE: The caller is responsible to close the response's body.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address to retrieve.
+//   - `aHeaders`: Additional request headers (may be `nil`).
+//
+// Returns:
+//   - `*http.Response`: The server's response (of any status).
+//   - `error`: A possible processing error.
+func httpDo(aContext context.Context, aOptions *TScreenshotParams, aURL string, aHeaders map[string]string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(aContext, http.MethodGet, aURL, nil)
+	if nil != err {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", aOptions.UserAgent)
+	if 0 < len(aOptions.AcceptLanguage) {
+		request.Header.Set("Accept-Language", aOptions.AcceptLanguage)
+	}
+	for key, value := range ssHeaders {
+		request.Header.Set(key, value)
+	}
+	for key, value := range aHeaders {
+		request.Header.Set(key, value)
+	}
+
+	client := httpClient()
+	response, err := client.Do(request)
+	if nil != err {
+		return nil, err
+	}
+	if http.StatusUnauthorized == response.StatusCode {
+		if auth := authHeader(request, response); 0 < len(auth) {
+			response.Body.Close()
+			request = request.Clone(aContext)
+			request.Header.Set("Authorization", auth)
+			if response, err = client.Do(request); nil != err {
+				return nil, err
+			}
+		}
+	}
+
+	return response, nil
+} // httpDo()
+
+// `httpGet()` retrieves `aURL` (see `httpDo()`) failing with a
+// [TStatusError] unless the server answers with `200 OK`.
+//
+// NOTE: The caller is responsible to close the response's body.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address to retrieve.
+//
+// Returns:
+//   - `*http.Response`: The server's response.
+//   - `error`: A possible processing error.
+func httpGet(aContext context.Context, aOptions *TScreenshotParams, aURL string) (*http.Response, error) {
+	response, err := httpDo(aContext, aOptions, aURL, nil)
+	if nil != err {
+		return nil, err
+	}
+	if http.StatusOK != response.StatusCode {
+		response.Body.Close()
+		return nil, &TStatusError{
+			Status:     response.Status,
+			StatusCode: response.StatusCode,
+			URL:        aURL,
+		}
+	}
+
+	return response, nil
+} // httpGet()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `AcceptLanguage()` returns the value of the `Accept-Language` header
+// sent with each request.
+//
+// Returns:
+//   - `string`: The configured language preferences.
+func AcceptLanguage() string {
+	return ssOptions.AcceptLanguage
+} // AcceptLanguage()
+
+// `SetAcceptLanguage()` configures the value of the `Accept-Language`
+// header sent with each request (e.g. `de-DE,de;q=0.9,en;q=0.8`).
+//
+// The value is used by the browser (incl. `navigator.languages`) as
+// well as for direct downloads.
+// An empty value uses the browser's default.
+//
+// Parameters:
+//   - `aLanguage`: The language preferences to use.
+func SetAcceptLanguage(aLanguage string) {
+	ssOptions.AcceptLanguage = strings.TrimSpace(aLanguage)
+} // SetAcceptLanguage()
+
+// `Headers()` returns the additional HTTP headers sent with each
+// request.
+//
+// Returns:
+//   - `map[string]string`: A copy of the configured headers.
+func Headers() map[string]string {
+	result := make(map[string]string, len(ssHeaders))
+	for key, value := range ssHeaders {
+		result[key] = value
+	}
+
+	return result
+} // Headers()
+
+// `SetHeaders()` configures additional HTTP headers to send with each
+// request (e.g. `DNT` or an API key).
+//
+// The headers are used by the browser as well as for direct downloads;
+// they replace any previously configured headers.
+// Passing `nil` (or an empty map) removes them.
+//
+// Parameters:
+//   - `aHeaders`: The headers' names and values.
+func SetHeaders(aHeaders map[string]string) {
+	ssHeaders = make(map[string]string, len(aHeaders))
+	for key, value := range aHeaders {
+		if key = strings.TrimSpace(key); 0 < len(key) {
+			ssHeaders[http.CanonicalHeaderKey(key)] = value
+		}
+	}
+} // SetHeaders()
+
+/* _EoF_ */
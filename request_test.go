@@ -0,0 +1,106 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_httpGet(t *testing.T) {
+	const (
+		user     = "reader"
+		password = "secret"
+		realm    = "test"
+		nonce    = "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+	)
+	md5hex := func(aData string) string {
+		sum := md5.Sum([]byte(aData))
+		return hex.EncodeToString(sum[:])
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(aWriter http.ResponseWriter, aRequest *http.Request) {
+		if ("de" != aRequest.Header.Get("Accept-Language")) ||
+			("1" != aRequest.Header.Get("Dnt")) {
+			http.Error(aWriter, "missing header", http.StatusBadRequest)
+			return
+		}
+		switch aRequest.URL.Path {
+		case "/basic":
+			if u, p, ok := aRequest.BasicAuth(); ok && (user == u) && (password == p) {
+				return
+			}
+			aWriter.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+
+		case "/digest":
+			scheme, params := parseChallenge(aRequest.Header.Get("Authorization"))
+			if "digest" == scheme {
+				ha1 := md5hex(user + ":" + realm + ":" + password)
+				ha2 := md5hex(aRequest.Method + ":" + params["uri"])
+				want := md5hex(ha1 + ":" + nonce + ":" + params["nc"] + ":" +
+					params["cnonce"] + ":auth:" + ha2)
+				if want == params["response"] {
+					return
+				}
+			}
+			aWriter.Header().Set("WWW-Authenticate",
+				`Digest realm="`+realm+`", qop="auth", nonce="`+nonce+`"`)
+
+		default:
+			return
+		}
+		http.Error(aWriter, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	SetAcceptLanguage("de")
+	SetHeaders(map[string]string{"DNT": "1"})
+	SetCredentialsFunc(func(aHost string) (string, string) {
+		return user, password
+	})
+	defer func() {
+		SetAcceptLanguage("")
+		SetHeaders(nil)
+		SetCredentialsFunc(nil)
+	}()
+
+	tests := []struct {
+		name    string
+		aURL    string
+		wantErr bool
+	}{
+		{"1", server.URL + "/", false},
+		{"2", server.URL + "/basic", false},
+		{"3", server.URL + "/digest", false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := httpGet(context.Background(), ssOptions, tt.aURL)
+			if (nil != err) != tt.wantErr {
+				t.Errorf("%q: httpGet() error = %v, wantErr %v",
+					tt.name, err, tt.wantErr)
+				return
+			}
+			if nil != response {
+				response.Body.Close()
+			}
+		})
+	}
+
+	SetCredentialsFunc(nil)
+	if response, err := httpGet(context.Background(), ssOptions, server.URL+"/basic"); nil == err {
+		response.Body.Close()
+		t.Error("httpGet() w/o credentials: expected error")
+	}
+} // Test_httpGet()
+
+/* _EoF_ */
@@ -0,0 +1,136 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Filename extension of the sidecar files:
+	sidecarExt = `.json`
+
+	// Image taken from an already existing file.
+	SourceCache = `cache`
+
+	// Image downloaded as-is because the URL pointed to an image.
+	SourceDownload = `download`
+
+	// Image taken from the web page's `og:image` metadata.
+	SourceOGImage = `og:image`
+
+	// Image rendered by the browser.
+	SourceScreenshot = `screenshot`
+)
+
+type (
+	// TCaptureResult describes the outcome of a single [Capture] call.
+	//
+	// If the [Sidecar] option is set this data is stored in a JSON
+	// file next to the image file.
+	TCaptureResult struct {
+		// Number of attempts needed for the capture
+		// (see [SetRetryAttempts]).
+		Attempts int `json:"attempts,omitempty"`
+
+		// Number of requests blocked during page processing
+		// (see [SetBlockFile]).
+		BlockedRequests int `json:"blockedRequests,omitempty"`
+
+		// Whether an already existing image file was used.
+		Cached bool `json:"cached"`
+
+		// The cookie-consent rules applied to the page
+		// (see [SetConsentFile]).
+		ConsentRules []string `json:"consentRules,omitempty"`
+
+		// The MIME type of the processed resource (see [SetMimePolicy]).
+		ContentType string `json:"contentType,omitempty"`
+
+		// Name of the image file (without path) in [ImageDir].
+		Filename string `json:"filename"`
+
+		// The address of the main document after following
+		// redirects.
+		FinalURL string `json:"finalURL,omitempty"`
+
+		// Whether the page was captured again with JavaScript
+		// because it didn't render without it (see [SetLearnJSfile]).
+		LearnedJS bool `json:"learnedJS,omitempty"`
+
+		// The options applied by the matching capture profiles
+		// (see [SetProfilesFile]).
+		Profile map[string]any `json:"profile,omitempty"`
+
+		// Errors of the scripts run during page processing
+		// (see [SetScriptsFile]).
+		ScriptErrors []string `json:"scriptErrors,omitempty"`
+
+		// Where the image came from (`screenshot`, `og:image`, …).
+		Source string `json:"source"`
+
+		// The HTTP status of the main document (see [SetErrorPages]).
+		Status int `json:"status,omitempty"`
+
+		// Time the image was created.
+		Time time.Time `json:"time"`
+
+		// The address of the processed web page.
+		URL string `json:"url"`
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `readSidecar()` reads the sidecar file belonging to the image
+// file `aFilename`.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the image.
+//
+// Returns:
+//   - `*TCaptureResult`: The stored capture result or `nil` if not available.
+func readSidecar(aFilename string) *TCaptureResult {
+	data, err := os.ReadFile(aFilename + sidecarExt)
+	if (nil != err) || (0 == len(data)) {
+		return nil
+	}
+
+	result := &TCaptureResult{}
+	if err = json.Unmarshal(data, result); nil != err {
+		return nil
+	}
+
+	return result
+} // readSidecar()
+
+// `writeSidecar()` stores `aResult` in the sidecar file belonging
+// to the image file `aFilename`.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the image.
+//   - `aResult`: The capture result to store.
+//
+// Returns:
+//   - `error`: A possible error during the operation.
+func writeSidecar(aFilename string, aResult *TCaptureResult) error {
+	data, err := json.MarshalIndent(aResult, "", "\t")
+	if nil != err {
+		return err
+	}
+
+	return os.WriteFile(aFilename+sidecarExt, data, fs.FileMode(0640))
+} // writeSidecar()
+
+/* _EoF_ */
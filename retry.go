@@ -0,0 +1,307 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Retry captures failing because the browser crashed or hung.
+	RetryBrowser = `browser`
+
+	// Retry captures failing because of network problems.
+	RetryNetwork = `network`
+
+	// Retry captures failing because of a server error (HTTP 5xx).
+	RetryServer = `server`
+
+	// Retry captures failing because of a timeout.
+	RetryTimeout = `timeout`
+
+	// Upper limit of the delay between two attempts:
+	maxRetryDelay = 30 * time.Second
+)
+
+type (
+	// TStatusError is returned if a server answers a request with
+	// an HTTP status other than `200 OK`.
+	TStatusError struct {
+		// The status line returned by the server (e.g. `404 Not Found`).
+		Status string
+
+		// The status code returned by the server.
+		StatusCode int
+
+		// The requested address.
+		URL string
+	}
+)
+
+// `Error()` returns the error's message.
+//
+// Returns:
+//   - `string`: The error message.
+func (se *TStatusError) Error() string {
+	return ssLibName + ": '" + se.URL + "' returned " + se.Status
+} // Error()
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `errorClass()` returns the class of `aErr` to be matched against
+// the [RetryOn] setting.
+//
+// Parameters:
+//   - `aErr`: The error to classify.
+//
+// Returns:
+//   - `string`: The error class or an empty string for permanent errors.
+func errorClass(aErr error) string {
+	if nil == aErr {
+		return ""
+	}
+
+	var statusErr *TStatusError
+	if errors.As(aErr, &statusErr) {
+		if http.StatusInternalServerError <= statusErr.StatusCode {
+			return RetryServer
+		}
+		return "" // 4xx responses are permanent
+	}
+
+	var netErr net.Error
+	if errors.Is(aErr, context.DeadlineExceeded) ||
+		(errors.As(aErr, &netErr) && netErr.Timeout()) {
+		return RetryTimeout
+	}
+	if errors.Is(aErr, ErrChromeErrorPage) {
+		return RetryNetwork
+	}
+	if errors.Is(aErr, ErrBrowserFailure) ||
+		errors.Is(aErr, chromedp.ErrChannelClosed) ||
+		errors.Is(aErr, chromedp.ErrInvalidContext) {
+		return RetryBrowser
+	}
+
+	var opErr *net.OpError
+	if errors.As(aErr, &opErr) {
+		return RetryNetwork
+	}
+	var dnsErr *net.DNSError
+	if errors.As(aErr, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return "" // the host doesn't exist
+		}
+		return RetryNetwork
+	}
+
+	// Navigation errors reported by the browser:
+	msg := aErr.Error()
+	switch {
+	case strings.Contains(msg, "net::ERR_TIMED_OUT"):
+		return RetryTimeout
+
+	case strings.Contains(msg, "net::ERR_NAME_NOT_RESOLVED"):
+		return ""
+
+	case strings.Contains(msg, "net::ERR_CONNECTION_"),
+		strings.Contains(msg, "net::ERR_NETWORK_"),
+		strings.Contains(msg, "net::ERR_INTERNET_DISCONNECTED"),
+		strings.Contains(msg, "net::ERR_EMPTY_RESPONSE"):
+		return RetryNetwork
+	}
+
+	return ""
+} // errorClass()
+
+// `retryClasses()` returns the known error classes of `aClasses`.
+//
+// Parameters:
+//   - `aClasses`: The comma separated list of error classes.
+//
+// Returns:
+//   - `string`: The comma separated list of known error classes.
+func retryClasses(aClasses string) string {
+	var list []string
+	for _, class := range strings.Split(strings.ToLower(aClasses), ",") {
+		switch class = strings.TrimSpace(class); class {
+		case RetryBrowser, RetryNetwork, RetryServer, RetryTimeout:
+			list = append(list, class)
+		}
+	}
+
+	return strings.Join(list, ",")
+} // retryClasses()
+
+// `retryable()` returns whether a capture failing with `aErr` should
+// be retried according to the [RetryOn] setting.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aErr`: The error of the failed attempt.
+//
+// Returns:
+//   - `bool`: Whether to retry the capture.
+func retryable(aOptions *TScreenshotParams, aErr error) bool {
+	class := errorClass(aErr)
+	if 0 == len(class) {
+		return false
+	}
+	for _, c := range strings.Split(aOptions.RetryOn, ",") {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
+} // retryable()
+
+// `retryDelay()` returns the time to wait before the attempt following
+// attempt number `aAttempt`.
+//
+// The delay grows exponentially with each attempt starting with
+// [RetryDelay]; a random jitter of ±50 % avoids several failing
+// captures hitting the same server at the same time.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aAttempt`: The number of the failed attempt (starting with `1`).
+//
+// Returns:
+//   - `time.Duration`: The time to wait.
+func retryDelay(aOptions *TScreenshotParams, aAttempt int) time.Duration {
+	delay := time.Duration(aOptions.RetryDelay) * time.Millisecond
+	for i := 1; (i < aAttempt) && (delay < maxRetryDelay); i++ {
+		delay <<= 1
+	}
+	if 0 >= delay {
+		return 0
+	}
+	delay = delay/2 + rand.N(delay) // #nosec G404
+
+	return min(delay, maxRetryDelay)
+} // retryDelay()
+
+// `withRetry()` calls `aFunc` until it succeeds, fails with a
+// permanent error, or [RetryAttempts] is reached.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the processed web page (for logging).
+//   - `aFunc`: The function performing a single attempt.
+//
+// Returns:
+//   - `int`: The number of attempts made.
+//   - `error`: The error of the last attempt.
+func withRetry(aOptions *TScreenshotParams, aURL string, aFunc func() error) (rAttempts int, rErr error) {
+	for {
+		rAttempts++
+		if rErr = aFunc(); nil == rErr {
+			return
+		}
+		if (rAttempts >= aOptions.RetryAttempts) || !retryable(aOptions, rErr) {
+			return
+		}
+
+		delay := retryDelay(aOptions, rAttempts)
+		log.Printf("%s: attempt %d/%d for '%s' failed (%s), retrying in %v: %v",
+			ssLibName, rAttempts, aOptions.RetryAttempts, aURL,
+			errorClass(rErr), delay.Round(time.Millisecond), rErr)
+		time.Sleep(delay)
+	}
+} // withRetry()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `RetryAttempts()` returns the max. number of attempts for a capture.
+//
+// Returns:
+//   - `int`: The max. number of attempts.
+func RetryAttempts() int {
+	return ssOptions.RetryAttempts
+} // RetryAttempts()
+
+// `SetRetryAttempts()` configures the max. number of attempts for a
+// capture failing with a transient error (see [SetRetryOn]).
+//
+// The default `1` disables retries.
+//
+// Parameters:
+//   - `anAttempts`: The max. number of attempts.
+func SetRetryAttempts(anAttempts int) {
+	if 1 > anAttempts {
+		anAttempts = 1
+	}
+	ssOptions.RetryAttempts = anAttempts
+} // SetRetryAttempts()
+
+// `RetryDelay()` returns the delay (in milliseconds) before the first
+// retry of a failed capture.
+//
+// Returns:
+//   - `int`: The initial delay.
+func RetryDelay() int {
+	return ssOptions.RetryDelay
+} // RetryDelay()
+
+// `SetRetryDelay()` configures the delay (in milliseconds) before the
+// first retry of a failed capture.
+//
+// The delay doubles with each further attempt (up to 30 seconds) and
+// is randomised by ±50 %.
+//
+// Parameters:
+//   - `aDelay`: The initial delay.
+func SetRetryDelay(aDelay int) {
+	if 0 > aDelay {
+		aDelay = 0
+	}
+	ssOptions.RetryDelay = aDelay
+} // SetRetryDelay()
+
+// `RetryOn()` returns the classes of errors for which a failed capture
+// is retried.
+//
+// Returns:
+//   - `string`: The comma separated list of error classes.
+func RetryOn() string {
+	return ssOptions.RetryOn
+} // RetryOn()
+
+// `SetRetryOn()` configures the classes of errors for which a failed
+// capture is retried (see [SetRetryAttempts]):
+//
+//   - [RetryBrowser]: the browser crashed, hung, or lost its connection,
+//   - [RetryNetwork]: the connection failed or was reset,
+//   - [RetryServer]: the server answered with an HTTP 5xx status,
+//   - [RetryTimeout]: the capture took longer than [MaxProcessTime].
+//
+// All other errors (like an excluded content type, an unknown
+// host, or an HTTP 4xx status) are permanent and never retried.
+// Unknown class names are ignored.
+//
+// Parameters:
+//   - `aClasses`: The comma separated list of error classes.
+func SetRetryOn(aClasses string) {
+	ssOptions.RetryOn = retryClasses(aClasses)
+} // SetRetryOn()
+
+/* _EoF_ */
@@ -0,0 +1,147 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func Test_errorClass(t *testing.T) {
+	tests := []struct {
+		name string
+		aErr error
+		want string
+	}{
+		{"1", nil, ""},
+		{"2", errors.New(ssLibName + ": excluded filename extension '.pdf'"), ""},
+		{"3", &TStatusError{Status: "404 Not Found", StatusCode: 404}, ""},
+		{"4", &TStatusError{Status: "503 Service Unavailable", StatusCode: 503}, RetryServer},
+		{"5", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), RetryTimeout},
+		{"6", errors.Join(ErrBrowserFailure, errors.New("websocket closed")), RetryBrowser},
+		{"7", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, RetryNetwork},
+		{"8", &net.DNSError{Name: "nowhere.invalid", IsNotFound: true}, ""},
+		{"9", errors.New("page load error net::ERR_CONNECTION_RESET"), RetryNetwork},
+		{"10", errors.New("page load error net::ERR_NAME_NOT_RESOLVED"), ""},
+		{"11", errors.New("page load error net::ERR_TIMED_OUT"), RetryTimeout},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorClass(tt.aErr); got != tt.want {
+				t.Errorf("%q: errorClass() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_errorClass()
+
+func Test_retryDelay(t *testing.T) {
+	defer SetRetryDelay(RetryDelay())
+
+	tests := []struct {
+		name     string
+		aDelay   int
+		aAttempt int
+		wantMin  time.Duration
+		wantMax  time.Duration
+	}{
+		{"1", 0, 1, 0, 0},
+		{"2", 100, 1, 50 * time.Millisecond, 150 * time.Millisecond},
+		{"3", 100, 3, 200 * time.Millisecond, 600 * time.Millisecond},
+		{"4", 1000, 20, 15 * time.Second, maxRetryDelay},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRetryDelay(tt.aDelay)
+			if got := retryDelay(ssOptions, tt.aAttempt); (got < tt.wantMin) || (got > tt.wantMax) {
+				t.Errorf("%q: retryDelay() = %v, want %v … %v",
+					tt.name, got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+} // Test_retryDelay()
+
+func Test_withRetry(t *testing.T) {
+	defer func(aAttempts, aDelay int, aOn string) {
+		SetRetryAttempts(aAttempts)
+		SetRetryDelay(aDelay)
+		SetRetryOn(aOn)
+	}(RetryAttempts(), RetryDelay(), RetryOn())
+	SetRetryDelay(0)
+
+	transient := &TStatusError{Status: "502 Bad Gateway", StatusCode: 502}
+	permanent := &TStatusError{Status: "403 Forbidden", StatusCode: 403}
+
+	tests := []struct {
+		name      string
+		aAttempts int
+		aOn       string
+		aErrors   []error
+		want      int
+		wantErr   bool
+	}{
+		{"1", 3, RetryServer, []error{nil}, 1, false},
+		{"2", 3, RetryServer, []error{transient, nil}, 2, false},
+		{"3", 3, RetryServer, []error{transient, transient, transient, nil}, 3, true},
+		{"4", 3, RetryServer, []error{permanent, nil}, 1, true},
+		{"5", 3, RetryNetwork, []error{transient, nil}, 1, true},
+		{"6", 1, RetryServer, []error{transient, nil}, 1, true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRetryAttempts(tt.aAttempts)
+			SetRetryOn(tt.aOn)
+			calls := 0
+			got, err := withRetry(ssOptions, "https://example.com/", func() error {
+				calls++
+				return tt.aErrors[calls-1]
+			})
+			if (nil != err) != tt.wantErr {
+				t.Errorf("%q: withRetry() error = %v, wantErr %v",
+					tt.name, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("%q: withRetry() = %d, want %d",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_withRetry()
+
+func TestSetRetryOn(t *testing.T) {
+	defer SetRetryOn(RetryOn())
+
+	tests := []struct {
+		name     string
+		aClasses string
+		want     string
+	}{
+		{"1", "", ""},
+		{"2", " Network , TIMEOUT", "network,timeout"},
+		{"3", "server,unknown,browser", "server,browser"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRetryOn(tt.aClasses)
+			if got := RetryOn(); got != tt.want {
+				t.Errorf("%q: RetryOn() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // TestSetRetryOn()
+
+/* _EoF_ */
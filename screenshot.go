@@ -25,10 +25,12 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/security"
 	"github.com/chromedp/chromedp"
 	"github.com/chromedp/chromedp/device"
@@ -58,23 +60,116 @@ const (
 	ssLibName = `ScreenShot`
 )
 
+const (
+	// Use only a rendered screenshot of the web page (default).
+	PreviewScreenshot TPreviewSource = iota
+
+	// Use only the web page's `og:image`.
+	PreviewOGImage
+
+	// Use the web page's `og:image` falling back to a screenshot.
+	PreviewOGImageFirst
+
+	// Use a screenshot falling back to the web page's `og:image`.
+	PreviewScreenshotFirst
+)
+
 // TScreenshotParams bundles all available configuration options
 // and pass them to the `Setup()` function in a single call.
 type (
+	// TPreviewSource determines where a web page's preview image
+	// is taken from.
+	TPreviewSource int
+
 	TScreenshotParams struct {
+		// Value of the `Accept-Language` header to send
+		// (empty for the browser's default).
+		AcceptLanguage string
+
 		// Flag whether to accept the respective other image format
 		AcceptOther bool
 
+		// Percentage of same-coloured pixels above which an
+		// image is considered blank (see [SetBlankRatio]).
+		BlankRatio int
+
+		// Max. colour difference of pixels considered the same
+		// colour (see [SetBlankTolerance]).
+		BlankTolerance int
+
+		// Path/filename of the rules which requests of a web page
+		// to block (see [SetBlockFile]).
+		BlockFile string
+
+		// Max. resident memory (in MB) of the reused browser
+		// (see [SetBrowserMaxRSS]).
+		BrowserMaxRSS int
+
+		// Number of captures after which the reused browser is
+		// restarted (see [SetBrowserRecycle]).
+		BrowserRecycle int
+
+		// Flag whether to reuse a single browser for all captures
+		// (see [SetBrowserReuse]).
+		BrowserReuse bool
+
+		// Comma separated list of query parameters to ignore when
+		// looking up a cached image (see [SetCanonicalParams]).
+		CanonicalParams string
+
+		// Whether `http` and `https` URLs share the cached image
+		// (see [SetCanonicalScheme]).
+		CanonicalScheme bool
+
 		// Flag whether certificate errors should be processed.
 		CertErrors bool
 
+		// Additional command-line flags for launching a local
+		// browser (see [SetChromeFlags]).
+		ChromeFlags string
+
+		// Path/filename of the rules to suppress cookie-consent
+		// banners (see [SetConsentFile]).
+		ConsentFile string
+
+		// Path/filename of the cookies to load before retrieving
+		// a web page (see [SetCookieFile]).
+		CookieFile string
+
 		// Dis-/Allow use of web cookies
 		Cookies bool
 
+		// Path/filename of the credentials for HTTP authentication
+		// (see [SetCredentialsFile]).
+		CredentialsFile string
+
+		// How to handle screenshots of HTTP error pages
+		// (see [SetErrorPages]).
+		ErrorPages TErrorPageMode
+
+		// Time (in minutes) after which an error page gets
+		// replaced (see [SetErrorTTL]).
+		ErrorTTL int
+
+		// Path of the local browser executable (empty for
+		// auto-detection).
+		ExecPath string
+
+		// Flag whether to run a local browser without window.
+		Headless bool
+
+		// Comma separated list of CSS selectors of elements to
+		// hide on all pages (see [SetHideSelectors]).
+		HideSelectors string
+
 		// Path/filename of a list of web hosts/domains where JavaScript
 		// running should be avoided (defaults to a file in user's homedir).
 		HostsAvoidJSfile string
 
+		// Path/filename of a list of web hosts/domains learned to
+		// require JavaScript (see [SetLearnJSfile]).
+		HostsLearnJSfile string
+
 		// Path/filename of a list of web hosts/domains where JavaScript
 		// is required to work (defaults to a file in user's homedir).
 		HostsNeedJSfile string
@@ -107,6 +202,10 @@ type (
 		// Timeout (in seconds) for page processing.
 		MaxProcessTime int
 
+		// Comma separated list of `type=action` entries overriding the
+		// built-in MIME policy (see [SetMimePolicy]).
+		MimePolicy string
+
 		// Flag whether to emulate a mobile device or not.
 		// This includes viewport meta tag, overlay scrollbars, text
 		// autosizing and more.
@@ -115,19 +214,91 @@ type (
 		// The identifier the JavaScript `navigator.platform` should return.
 		Platform string
 
+		// The source of the preview image (screenshot and/or `og:image`).
+		PreviewSource TPreviewSource
+
+		// Directory of the persistent browser profile(s)
+		// (see [SetProfileDir]).
+		ProfileDir string
+
+		// Which browser profile to use for a capture.
+		ProfileMode TProfileMode
+
+		// Path/filename of the per-host capture profiles
+		// (see [SetProfilesFile]).
+		ProfilesFile string
+
+		// URL of the proxy to use (see [SetProxy]).
+		Proxy string
+
+		// Comma separated list of hosts/domains to access
+		// without proxy (see [SetProxyBypass]).
+		ProxyBypass string
+
+		// Path/filename of the rules selecting the proxy for
+		// certain hosts (see [SetProxyFile]).
+		ProxyFile string
+
+		// DevTools URL of a running browser to use instead of
+		// launching a local one (see [SetRemoteURL]).
+		RemoteURL string
+
+		// Max. number of attempts for a capture
+		// (see [SetRetryAttempts]).
+		RetryAttempts int
+
+		// Delay (in milliseconds) before the first retry
+		// (see [SetRetryDelay]).
+		RetryDelay int
+
+		// Comma separated list of error classes to retry
+		// (see [SetRetryOn]).
+		RetryOn string
+
+		// Path/filename of a list of scripts to run during page
+		// processing (see [SetScriptsFile]).
+		ScriptsFile string
+
 		// Flag whether to show the scraped web-page's scrollbars.
 		Scrollbars bool
 
+		// Flag whether to write a JSON sidecar file with the
+		// capture result next to the image file.
+		Sidecar bool
+
+		// Path/filename of a list of user stylesheets and elements
+		// to hide during page processing (see [SetStylesFile]).
+		StylesFile string
+
 		// User Agent to use when queuing external sites.
 		UserAgent string
+
+		// CSS selector of an element to wait for before taking
+		// the screenshot (see [SetWaitSelector]).
+		WaitSelector string
+
+		// Time (in milliseconds) to wait for a page to render
+		// (see [SetWaitTime]).
+		WaitTime int
 	}
 
 	tAvoidNeedFile struct {
+		sync.Mutex
+
+		// Name of the file the list was read from:
+		filename string
+
 		// Time of next reading a Avoid/Need hosts file:
 		nextTime time.Time
 
 		// List of hosts to test against:
 		list sort.StringSlice
+
+		// The compiled host patterns of `list`:
+		patterns []*tHostPattern
+
+		// Whether the file is watched for changes:
+		watched bool
 	}
 )
 
@@ -154,10 +325,29 @@ var (
 
 	// The initially used screenshot options:
 	ssOptions *TScreenshotParams = &TScreenshotParams{
+		AcceptLanguage:   "",
 		AcceptOther:      true,
+		BlankRatio:       99,
+		BlankTolerance:   8,
+		BlockFile:        "",
+		BrowserMaxRSS:    0,
+		BrowserRecycle:   0,
+		BrowserReuse:     false,
+		CanonicalParams:  defaultCanonicalParams,
+		CanonicalScheme:  false,
 		CertErrors:       false,
+		ChromeFlags:      "",
+		ConsentFile:      ssConsent.filename,
+		CookieFile:       "",
 		Cookies:          false,
+		CredentialsFile:  "",
+		ErrorPages:       ErrorPageFail,
+		ErrorTTL:         60,
+		ExecPath:         "",
+		Headless:         true,
+		HideSelectors:    "",
 		HostsAvoidJSfile: setHosts4JS("./", defaultHostsAvoidJS),
+		HostsLearnJSfile: "",
 		HostsNeedJSfile:  setHosts4JS("./", defaultHostsNeedJS),
 		ImageAge:         0,
 		ImageDir:         os.TempDir(),
@@ -168,10 +358,27 @@ var (
 		ImageWidth:       defaultImageWidth,
 		JavaScript:       false,
 		MaxProcessTime:   32,
+		MimePolicy:       "",
 		Mobile:           false,
 		Platform:         defaultPlatform,
+		PreviewSource:    PreviewScreenshot,
+		ProfileDir:       "",
+		ProfileMode:      ProfileEphemeral,
+		ProfilesFile:     "",
+		Proxy:            "",
+		ProxyBypass:      "",
+		ProxyFile:        "",
+		RemoteURL:        "",
+		RetryAttempts:    1,
+		RetryDelay:       500,
+		RetryOn:          "browser,network,server,timeout",
+		ScriptsFile:      "",
 		Scrollbars:       false,
+		Sidecar:          false,
+		StylesFile:       "",
 		UserAgent:        DefaultAgent,
+		WaitSelector:     "",
+		WaitTime:         0,
 	}
 
 	// Number of minutes to wait before re-reading Avoid/Need hosts files:
@@ -181,6 +388,23 @@ var (
 	ssReplaceNonAlphasRE = regexp.MustCompile(`\W+`)
 )
 
+// `String()` returns the name of the preview source.
+//
+// Returns:
+//   - `string`: The preview source's name.
+func (ps TPreviewSource) String() string {
+	switch ps {
+	case PreviewOGImage:
+		return "og:image"
+	case PreviewOGImageFirst:
+		return "og:image,screenshot"
+	case PreviewScreenshotFirst:
+		return "screenshot,og:image"
+	default:
+		return "screenshot"
+	}
+} // String()
+
 // `Do()` uses its options' values to configure the runtime options for
 // taking screenshots.
 //
@@ -217,10 +441,29 @@ func (sso *TScreenshotParams) Do() *TScreenshotParams {
 		return Options() // nothing to change
 	}
 
+	SetAcceptLanguage(sso.AcceptLanguage)
 	ssOptions.AcceptOther = sso.AcceptOther
+	SetBlankRatio(sso.BlankRatio)
+	SetBlankTolerance(sso.BlankTolerance)
+	SetBlockFile(sso.BlockFile)
+	SetBrowserMaxRSS(sso.BrowserMaxRSS)
+	SetBrowserRecycle(sso.BrowserRecycle)
+	SetBrowserReuse(sso.BrowserReuse)
+	SetCanonicalParams(sso.CanonicalParams)
+	ssOptions.CanonicalScheme = sso.CanonicalScheme
 	ssOptions.CertErrors = sso.CertErrors
+	SetChromeFlags(sso.ChromeFlags)
+	SetConsentFile(sso.ConsentFile)
+	SetCookieFile(sso.CookieFile)
 	ssOptions.Cookies = sso.Cookies
+	SetCredentialsFile(sso.CredentialsFile)
+	SetErrorPages(sso.ErrorPages)
+	SetErrorTTL(sso.ErrorTTL)
+	SetExecPath(sso.ExecPath)
+	ssOptions.Headless = sso.Headless
+	SetHideSelectors(sso.HideSelectors)
 	SetAvoidJSfile(sso.HostsAvoidJSfile)
+	SetLearnJSfile(sso.HostsLearnJSfile)
 	SetNeedJSfile(sso.HostsNeedJSfile)
 	SetImageAge(sso.ImageAge)
 	SetImageDir(sso.ImageDir)
@@ -231,10 +474,27 @@ func (sso *TScreenshotParams) Do() *TScreenshotParams {
 	SetImageWidth(sso.ImageWidth)
 	ssOptions.JavaScript = sso.JavaScript
 	SetMaxProcessTime(sso.MaxProcessTime)
+	SetMimePolicy(sso.MimePolicy)
 	ssOptions.Mobile = sso.Mobile
 	SetPlatform(sso.Platform)
+	SetPreviewSource(sso.PreviewSource)
+	SetProfileDir(sso.ProfileDir)
+	SetProfileMode(sso.ProfileMode)
+	SetProfilesFile(sso.ProfilesFile)
+	SetProxy(sso.Proxy)
+	SetProxyBypass(sso.ProxyBypass)
+	SetProxyFile(sso.ProxyFile)
+	SetRemoteURL(sso.RemoteURL)
+	SetRetryAttempts(sso.RetryAttempts)
+	SetRetryDelay(sso.RetryDelay)
+	SetRetryOn(sso.RetryOn)
+	SetScriptsFile(sso.ScriptsFile)
 	ssOptions.Scrollbars = sso.Scrollbars
+	ssOptions.Sidecar = sso.Sidecar
+	SetStylesFile(sso.StylesFile)
 	SetUserAgent(sso.UserAgent)
+	SetWaitSelector(sso.WaitSelector)
+	SetWaitTime(sso.WaitTime)
 
 	return Options()
 } // Do()
@@ -245,10 +505,29 @@ func (sso *TScreenshotParams) Do() *TScreenshotParams {
 //   - `*TScreenshotParams`: The currently configured screenshot options.
 func Options() *TScreenshotParams {
 	return &TScreenshotParams{
+		AcceptLanguage:   ssOptions.AcceptLanguage,
 		AcceptOther:      ssOptions.AcceptOther,
+		BlankRatio:       ssOptions.BlankRatio,
+		BlankTolerance:   ssOptions.BlankTolerance,
+		BlockFile:        ssOptions.BlockFile,
+		BrowserMaxRSS:    ssOptions.BrowserMaxRSS,
+		BrowserRecycle:   ssOptions.BrowserRecycle,
+		BrowserReuse:     ssOptions.BrowserReuse,
+		CanonicalParams:  ssOptions.CanonicalParams,
+		CanonicalScheme:  ssOptions.CanonicalScheme,
 		CertErrors:       ssOptions.CertErrors,
+		ChromeFlags:      ssOptions.ChromeFlags,
+		ConsentFile:      ssOptions.ConsentFile,
+		CookieFile:       ssOptions.CookieFile,
 		Cookies:          ssOptions.Cookies,
+		CredentialsFile:  ssOptions.CredentialsFile,
+		ErrorPages:       ssOptions.ErrorPages,
+		ErrorTTL:         ssOptions.ErrorTTL,
+		ExecPath:         ssOptions.ExecPath,
+		Headless:         ssOptions.Headless,
+		HideSelectors:    ssOptions.HideSelectors,
 		HostsAvoidJSfile: ssOptions.HostsAvoidJSfile,
+		HostsLearnJSfile: ssOptions.HostsLearnJSfile,
 		HostsNeedJSfile:  ssOptions.HostsNeedJSfile,
 		ImageAge:         ssOptions.ImageAge,
 		ImageDir:         ssOptions.ImageDir,
@@ -259,10 +538,27 @@ func Options() *TScreenshotParams {
 		ImageWidth:       ssOptions.ImageWidth,
 		JavaScript:       ssOptions.JavaScript,
 		MaxProcessTime:   ssOptions.MaxProcessTime,
+		MimePolicy:       ssOptions.MimePolicy,
 		Mobile:           ssOptions.Mobile,
 		Platform:         ssOptions.Platform,
+		PreviewSource:    ssOptions.PreviewSource,
+		ProfileDir:       ssOptions.ProfileDir,
+		ProfileMode:      ssOptions.ProfileMode,
+		ProfilesFile:     ssOptions.ProfilesFile,
+		Proxy:            ssOptions.Proxy,
+		ProxyBypass:      ssOptions.ProxyBypass,
+		ProxyFile:        ssOptions.ProxyFile,
+		RemoteURL:        ssOptions.RemoteURL,
+		RetryAttempts:    ssOptions.RetryAttempts,
+		RetryDelay:       ssOptions.RetryDelay,
+		RetryOn:          ssOptions.RetryOn,
+		ScriptsFile:      ssOptions.ScriptsFile,
 		Scrollbars:       ssOptions.Scrollbars,
+		Sidecar:          ssOptions.Sidecar,
+		StylesFile:       ssOptions.StylesFile,
 		UserAgent:        ssOptions.UserAgent,
+		WaitSelector:     ssOptions.WaitSelector,
+		WaitTime:         ssOptions.WaitTime,
 	}
 } // Options()
 
@@ -280,10 +576,29 @@ func String() string {
 	)
 	var sb strings.Builder
 
+	sb.WriteString(fmt.Sprintf(fmtStr, "AcceptLanguage", ssOptions.AcceptLanguage))
 	sb.WriteString(fmt.Sprintf(fmtBoo, "AcceptOther", ssOptions.AcceptOther))
+	sb.WriteString(fmt.Sprintf(fmtInt, "BlankRatio", ssOptions.BlankRatio))
+	sb.WriteString(fmt.Sprintf(fmtInt, "BlankTolerance", ssOptions.BlankTolerance))
+	sb.WriteString(fmt.Sprintf(fmtStr, "BlockFile", ssOptions.BlockFile))
+	sb.WriteString(fmt.Sprintf(fmtInt, "BrowserMaxRSS", ssOptions.BrowserMaxRSS))
+	sb.WriteString(fmt.Sprintf(fmtInt, "BrowserRecycle", ssOptions.BrowserRecycle))
+	sb.WriteString(fmt.Sprintf(fmtBoo, "BrowserReuse", ssOptions.BrowserReuse))
+	sb.WriteString(fmt.Sprintf(fmtStr, "CanonicalParams", ssOptions.CanonicalParams))
+	sb.WriteString(fmt.Sprintf(fmtBoo, "CanonicalScheme", ssOptions.CanonicalScheme))
 	sb.WriteString(fmt.Sprintf(fmtBoo, "CertErrors", ssOptions.CertErrors))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ChromeFlags", ssOptions.ChromeFlags))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ConsentFile", ssOptions.ConsentFile))
+	sb.WriteString(fmt.Sprintf(fmtStr, "CookieFile", ssOptions.CookieFile))
 	sb.WriteString(fmt.Sprintf(fmtBoo, "Cookies", ssOptions.Cookies))
+	sb.WriteString(fmt.Sprintf(fmtStr, "CredentialsFile", ssOptions.CredentialsFile))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ErrorPages", ssOptions.ErrorPages))
+	sb.WriteString(fmt.Sprintf(fmtInt, "ErrorTTL", ssOptions.ErrorTTL))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ExecPath", ssOptions.ExecPath))
+	sb.WriteString(fmt.Sprintf(fmtBoo, "Headless", ssOptions.Headless))
+	sb.WriteString(fmt.Sprintf(fmtStr, "HideSelectors", ssOptions.HideSelectors))
 	sb.WriteString(fmt.Sprintf(fmtStr, "HostsAvoidJSfile", ssOptions.HostsAvoidJSfile))
+	sb.WriteString(fmt.Sprintf(fmtStr, "HostsLearnJSfile", ssOptions.HostsLearnJSfile))
 	sb.WriteString(fmt.Sprintf(fmtStr, "HostsNeedJSfile", ssOptions.HostsNeedJSfile))
 	sb.WriteString(fmt.Sprintf(fmtInt, "ImageAge", ssOptions.ImageAge))
 	sb.WriteString(fmt.Sprintf(fmtStr, "ImageDir", ssOptions.ImageDir))
@@ -294,10 +609,27 @@ func String() string {
 	sb.WriteString(fmt.Sprintf(fmtInt, "ImageWidth", ssOptions.ImageWidth))
 	sb.WriteString(fmt.Sprintf(fmtBoo, "JavaScript", ssOptions.JavaScript))
 	sb.WriteString(fmt.Sprintf(fmtInt, "MaxProcessTime", ssOptions.MaxProcessTime))
+	sb.WriteString(fmt.Sprintf(fmtStr, "MimePolicy", ssOptions.MimePolicy))
 	sb.WriteString(fmt.Sprintf(fmtBoo, "Mobile", ssOptions.Mobile))
 	sb.WriteString(fmt.Sprintf(fmtStr, "Platform", ssOptions.Platform))
+	sb.WriteString(fmt.Sprintf(fmtStr, "PreviewSource", ssOptions.PreviewSource))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ProfileDir", ssOptions.ProfileDir))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ProfileMode", ssOptions.ProfileMode))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ProfilesFile", ssOptions.ProfilesFile))
+	sb.WriteString(fmt.Sprintf(fmtStr, "Proxy", ssOptions.Proxy))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ProxyBypass", ssOptions.ProxyBypass))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ProxyFile", ssOptions.ProxyFile))
+	sb.WriteString(fmt.Sprintf(fmtStr, "RemoteURL", ssOptions.RemoteURL))
+	sb.WriteString(fmt.Sprintf(fmtInt, "RetryAttempts", ssOptions.RetryAttempts))
+	sb.WriteString(fmt.Sprintf(fmtInt, "RetryDelay", ssOptions.RetryDelay))
+	sb.WriteString(fmt.Sprintf(fmtStr, "RetryOn", ssOptions.RetryOn))
+	sb.WriteString(fmt.Sprintf(fmtStr, "ScriptsFile", ssOptions.ScriptsFile))
 	sb.WriteString(fmt.Sprintf(fmtBoo, "Scrollbars", ssOptions.Scrollbars))
+	sb.WriteString(fmt.Sprintf(fmtBoo, "Sidecar", ssOptions.Sidecar))
+	sb.WriteString(fmt.Sprintf(fmtStr, "StylesFile", ssOptions.StylesFile))
 	sb.WriteString(fmt.Sprintf(fmtStr, "UserAgent", ssOptions.UserAgent))
+	sb.WriteString(fmt.Sprintf(fmtStr, "WaitSelector", ssOptions.WaitSelector))
+	sb.WriteString(fmt.Sprintf(fmtInt, "WaitTime", ssOptions.WaitTime))
 
 	return sb.String()
 } // String()
@@ -317,12 +649,7 @@ func String() string {
 // Returns:
 //   - `bool`: Whether `aURL` is part of `aHostsFilename` or not.
 func chk4(aURL, aHostsFilename string) bool {
-	var (
-		err    error
-		hosts  *tAvoidNeedFile
-		needle string
-		URL    *url.URL
-	)
+	var hosts *tAvoidNeedFile
 
 	// We can't use `switch` here since the order of tests is
 	// significant (which isn 't guaranteed with `switch`).
@@ -337,113 +664,222 @@ func chk4(aURL, aHostsFilename string) bool {
 		return false // unrecognised filename
 	}
 
-	if URL, err = url.Parse(aURL); nil != err {
+	host, port, path := urlParts(aURL)
+	if 0 == len(host) {
 		return false
 	}
-	if needle = URL.Hostname(); 0 == len(needle) {
-		// The given `aURL` is obviously not a full/correct URL
-		// but probably just a host name.
-		if needle = URL.Path; 0 == len(needle) {
-			return false
-		}
+
+	// Compile the patterns once per reading the file:
+	if hosts.due(aHostsFilename) {
+		hosts.reload()
 	}
 
-	if (0 == hosts.list.Len()) || time.Now().After(hosts.nextTime) {
-		if 0 < ssReadWaitTime {
-			hosts.nextTime = time.Now().Add(time.Duration(ssReadWaitTime) * time.Minute)
+	return hosts.matches(host, port, path)
+} // chk4()
+
+// `capture()` performs a single attempt to create the image of `aURL`
+// and to store it in [ImageDir].
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the web page to process.
+//   - `aSanitised`: The sanitised `aURL` used as the image's file name.
+//   - `aResult`: The capture result to receive processing details.
+//
+// Returns:
+//   - `error`: A possible error during creation of the screenshot image.
+func capture(aOptions *TScreenshotParams, aURL, aSanitised string, aResult *TCaptureResult) (rErr error) {
+	var (
+		// Declare variables here so we can use them in different
+		// contexts/closures below (and it eases debugging).
+		cancel    context.CancelFunc
+		ctx       context.Context
+		err       error
+		imageData []byte
+		response  *http.Response
+	)
+	fName := filepath.Join(ssOptions.ImageDir, aResult.Filename)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Duration(aOptions.MaxProcessTime)*time.Second)
+	defer func() {
+		if r := recover(); nil != r {
+			// Timing problems or invalid site data might indirectly
+			// cause the image generation to panic.
+			log.Println(ssLibName, err)
+			if nil == rErr {
+				rErr = errors.Join(ErrBrowserFailure,
+					fmt.Errorf("%s: panic processing '%s': %v", ssLibName, aURL, r))
+			}
 		}
-		if hosts.list = readListFile(aHostsFilename); 0 == hosts.list.Len() {
-			return false
+		cancel()
+	}()
+
+	// Decide how to process the resource by its content type:
+	ext := strings.ToLower(fileExt(aURL))
+	aResult.ContentType = contentType(ctx, aOptions, aURL)
+	switch mimeAction(aOptions, aResult.ContentType) {
+	case MimeReject:
+		return errors.New(ssLibName +
+			": excluded content type '" + aResult.ContentType + "' of '" + aURL + "'")
+
+	case MimeDownload:
+		if fPath, ok := localFile(aURL); ok {
+			if imageData, err = os.ReadFile(fPath); /* #nosec G304 */ nil != err {
+				return err
+			}
+		} else {
+			if response, err = httpGet(ctx, aOptions, aURL); nil != err {
+				return err
+			}
+			defer response.Body.Close()
+		}
+		aResult.Filename = aSanitised + downloadExt(aResult.ContentType, ext)
+		aResult.Source = SourceDownload
+		fName = filepath.Join(ssOptions.ImageDir, aResult.Filename)
+
+	case MimeConvert:
+		if imageData, err = convertImage(ctx, aOptions, aURL); nil != err {
+			return err
+		}
+		aResult.Source = SourceDownload
+
+	default:
+		if imageData, aResult.Source, err = previewImage(ctx, aOptions, aURL, aResult); nil != err {
+			return err
+		}
+		if (SourceScreenshot == aResult.Source) && isErrorStatus(aResult.Status) {
+			switch aOptions.ErrorPages {
+			case ErrorPageSeparate:
+				aResult.Filename = errorFilename(aResult.Filename)
+				fName = filepath.Join(ssOptions.ImageDir, aResult.Filename)
+
+			case ErrorPageTTL:
+				// `Capture()` writes the sidecar used by `exists()`
+
+			default:
+				return statusError(aResult)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err() // Canceled? TimeOut?
+
+		default:
+			break // still within our allocated time frame
 		}
 	}
 
-	return containsHost(strings.ToLower(needle), &hosts.list)
-} // chk4()
+	if (0 == len(imageData)) && (nil == response) {
+		return errors.New(ssLibName + ": no data received for '" +
+			fName + "'")
+	}
+
+	if err = writeFile(fName, imageData, response); nil != err {
+		// some problem during attempt to save image to disk
+		return err
+	}
+	aResult.Time = time.Now()
+
+	// Everything went well it seems …
+	return nil
+} // capture()
+
+// `cached()` completes `aResult` for the already existing image file
+// `aFilename`.
+//
+// If there's a sidecar file for `aFilename` its data is used.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the existing image.
+//   - `aResult`: The capture result to complete.
+//
+// Returns:
+//   - `*TCaptureResult`: The completed capture result.
+func cached(aFilename string, aResult *TCaptureResult) *TCaptureResult {
+	if stored := readSidecar(aFilename); nil != stored {
+		aResult.FinalURL = stored.FinalURL
+		aResult.Source = stored.Source
+		aResult.Status = stored.Status
+		aResult.Time = stored.Time
+	} else {
+		aResult.Source = SourceCache
+		if fi, err := os.Stat(aFilename); nil == err {
+			aResult.Time = fi.ModTime()
+		}
+	}
+	aResult.Cached = true
+
+	return aResult
+} // cached()
 
 // `cleanupOutput()` removes unneeded leading data from `aRawData`
 // and returns the properly encoded image data.
 //
+// Blank images (see [SetBlankRatio]) are discarded.
+//
 // Parameters:
+//   - `aOptions`: The options to use.
 //   - `aRawData`: The raw image data to cleanup.
 //
 // Returns:
-//   - `[]byte`: The `aRawData` w/o leading garbage.
-func cleanupOutput(aRawData []byte) []byte {
+//   - `[]byte`: The `aRawData` w/o leading garbage or `nil` if blank.
+func cleanupOutput(aOptions *TScreenshotParams, aRawData []byte) []byte {
 	if 0 == len(aRawData) {
 		return aRawData
 	}
 	var (
-		buffer  bytes.Buffer
 		decoded image.Image
 		err     error
 	)
 
-	if 100 == ssOptions.ImageQuality { // 'png' format
-		decoded, err = png.Decode(bytes.NewReader(aRawData))
-		for nil != err {
-			if aRawData = aRawData[1:]; 0 == len(aRawData) {
-				return aRawData // i.e. empty array
-			}
-			decoded, err = png.Decode(bytes.NewReader(aRawData))
-		}
-		decoded = cropScale(decoded) // adjust the image's size
-		_ = png.Encode(&buffer, decoded)
-	} else { // 'jpeg' format
-		decoded, err = jpeg.Decode(bytes.NewReader(aRawData))
-		for nil != err {
-			if aRawData = aRawData[1:]; 0 == len(aRawData) {
-				return aRawData // i.e. empty array
-			}
-			decoded, err = jpeg.Decode(bytes.NewReader(aRawData))
+	decode := jpeg.Decode
+	if 100 == aOptions.ImageQuality { // 'png' format
+		decode = png.Decode
+	}
+	decoded, err = decode(bytes.NewReader(aRawData))
+	for nil != err {
+		if aRawData = aRawData[1:]; 0 == len(aRawData) {
+			return aRawData // i.e. empty array
 		}
-		decoded = cropScale(decoded) // adjust the image's size
-		opts := jpeg.Options{Quality: ssOptions.ImageQuality}
-		_ = jpeg.Encode(&buffer, decoded, &opts)
+		decoded, err = decode(bytes.NewReader(aRawData))
 	}
 
-	if 4096 < buffer.Len() {
-		return buffer.Bytes()
+	if isBlank(aOptions, decoded) {
+		return nil
+	}
+
+	// adjust the image's size
+	if result := encodeImage(aOptions, cropScale(aOptions, decoded)); 0 < len(result) {
+		return result
 	}
 
 	return aRawData // i.e. original data
 } // cleanupOutput()
 
-// `configChrome()` sets up how to take a screenshot of the entire browser
-// viewport the size of which is determined by `ImageWidth()`/`ImageHeight()`.
+// `configBrowser()` sets up the virtual browser's emulation settings
+// for a viewport the size of which is determined by
+// `ImageWidth()`/`ImageHeight()`.
 //
 // Parameters:
-//   - `aURL`: The address of the web page to process.
-//   - `aResult`: Data structure to receive the generated screenshot image.
+//   - `aOptions`: The options to use.
+//   - `aEnableJS`: Whether to activate JavaScript in the browser.
 //
 // Returns:
 //   - `chromedp.Tasks`: A sequential list of Actions that can be used as a single Action.
-func configChrome(aURL string, aResult *[]byte) chromedp.Tasks {
-	enableJS := ssOptions.JavaScript
-	if enableJS {
-		// If the domain is found in the 'avoid' list then we
-		// do NOT want to activate JS here:
-		enableJS = !chk4(aURL, ssOptions.HostsAvoidJSfile)
-	} else {
-		// If the domain is found in the 'need' list then we
-		// DO want to activate JS here:
-		enableJS = chk4(aURL, ssOptions.HostsNeedJSfile)
-	}
-	waitDuration := time.Second << 1 // two seconds
-	if enableJS {
-		waitDuration <<= 1 // four seconds
-	}
+func configBrowser(aOptions *TScreenshotParams, aEnableJS bool) chromedp.Tasks {
 	var (
 		imgHeight, imgWidth int64
 		imgScale            float64
 	)
-	if 0 < ssOptions.ImageHeight {
-		imgHeight = int64(ssOptions.ImageHeight)
+	if 0 < aOptions.ImageHeight {
+		imgHeight = int64(aOptions.ImageHeight)
 	}
-	if 0 < ssOptions.ImageWidth {
-		imgWidth = int64(ssOptions.ImageWidth)
+	if 0 < aOptions.ImageWidth {
+		imgWidth = int64(aOptions.ImageWidth)
 	}
-	if 0 < ssOptions.ImageScale {
-		imgScale = ssOptions.ImageScale
+	if 0 < aOptions.ImageScale {
+		imgScale = aOptions.ImageScale
 	}
 
 	// Note: `chromedp.FullScreenshot()` overrides the device's
@@ -457,85 +893,99 @@ func configChrome(aURL string, aResult *[]byte) chromedp.Tasks {
 		emulation.ResetPageScaleFactor(),
 
 		// values of '0' will disable the override:
-		emulation.SetDeviceMetricsOverride(imgWidth, 0 /*imgHeight*/, imgScale, ssOptions.Mobile).
+		emulation.SetDeviceMetricsOverride(imgWidth, 0 /*imgHeight*/, imgScale, aOptions.Mobile).
 			WithScreenWidth(imgWidth).
 			WithScreenHeight(imgHeight),
 
 		// setup some browser options:
-		emulation.SetDocumentCookieDisabled(!ssOptions.Cookies),
+		emulation.SetDocumentCookieDisabled(!aOptions.Cookies),
 		emulation.SetEmitTouchEventsForMouse(false),
 		emulation.SetFocusEmulationEnabled(true),
 		emulation.SetIdleOverride(true, true),
-		emulation.SetScriptExecutionDisabled(!enableJS),
-		emulation.SetScrollbarsHidden(!ssOptions.Scrollbars),
+		emulation.SetScriptExecutionDisabled(!aEnableJS),
+		emulation.SetScrollbarsHidden(!aOptions.Scrollbars),
 		// ignore certificate errors (e.g. self-signed):
-		security.SetIgnoreCertificateErrors(!ssOptions.CertErrors),
+		security.SetIgnoreCertificateErrors(!aOptions.CertErrors),
 		// configure the UserAgent to pose as:
-		emulation.SetUserAgentOverride(ssOptions.UserAgent).
-			// WithAcceptLanguage("en").	//FIXME get proper value format
-			WithPlatform(ssOptions.Platform),
-
-		// perform the actual scraping action:
-		chromedp.Navigate(aURL),
-		chromedp.Sleep(waitDuration), // time to receive&render the page
-		chromedp.FullScreenshot(aResult, ssOptions.ImageQuality),
+		emulation.SetUserAgentOverride(aOptions.UserAgent).
+			WithAcceptLanguage(aOptions.AcceptLanguage).
+			WithPlatform(aOptions.Platform),
+		// additional headers to send with each request:
+		network.SetExtraHTTPHeaders(extraHeaders()),
 	}
-} // configChrome()
+} // configBrowser()
 
-// `containsHost()` returns whether `aNeedle` matches a line
-// in `aHaystack`.
-//
-// NOTE: This function doesn't check whether `aNeedle` is literally
-// found in `aHaystack` but checks whether `aNeedle` _ends_ with an
-// entry in `aHaystack`.
-// So both `example.com` and `mobile.example.com`(as needle) are matched
-// by the line `example.com` (in haystack) while only `mobile.example.com`
-// (as needle) would be matched by `.example.com` (in haystack).
+// `configChrome()` sets up how to take a screenshot of the entire browser
+// viewport the size of which is determined by `ImageWidth()`/`ImageHeight()`.
 //
 // Parameters:
-//   - `aNeedle`: The string to search in the provided list
-//   - `aHaystack`: The string list to walk through.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the web page to process.
+//   - `aResult`: Data structure to receive the generated screenshot image.
+//   - `aCapture`: The capture result to receive processing details.
 //
 // Returns:
-//   - `bool`: Whether `aNeedle` matches a line in `aHaystack`.
-func containsHost(aNeedle string, aHaystack *sort.StringSlice) bool {
-	for _, entry := range *aHaystack {
-		if (0 == len(entry)) || `#` == entry[0:1] {
-			continue // shouldn't happen: `readListFile()` removes
-			// those lines, but UnitTests might send such lists.
-		}
-		if strings.HasSuffix(aNeedle, entry) {
-			return true
-		}
+//   - `chromedp.Tasks`: A sequential list of Actions that can be used as a single Action.
+func configChrome(aOptions *TScreenshotParams, aURL string, aResult *[]byte, aCapture *TCaptureResult) chromedp.Tasks {
+	enableJS := useJavaScript(aOptions, aURL, aCapture.Profile)
+
+	fetchBefore, fetchAfter := fetchTasks(aURL, aCapture)
+	statusBefore, statusAfter := statusTasks(aCapture)
+	var before, after chromedp.Tasks
+	if enableJS {
+		before, after = scriptTasks(aURL, aCapture)
 	}
 
-	return false
-} // containsHost()
+	tasks := append(configBrowser(aOptions, enableJS), fetchBefore...)
+	tasks = append(tasks, statusBefore...)
+	tasks = append(tasks, cookieTasks()...)
+	tasks = append(tasks, before...)
+	tasks = append(tasks,
+		// perform the actual scraping action:
+		chromedp.Navigate(aURL),
+	)
+	tasks = append(tasks, styleTasks(styleSheet(aOptions, aURL))...)
+	tasks = append(tasks, waitTasks(aOptions, enableJS)...)
+	// consent banners are usually injected after loading the page:
+	tasks = append(tasks, consentTasks(aURL, enableJS, aCapture)...)
+	tasks = append(tasks, after...)
+	tasks = append(tasks, errorPageTasks(aURL)...)
+	tasks = append(tasks, fetchAfter...)
+	tasks = append(tasks, statusAfter...)
+	if !enableJS && learnJS(aOptions, aCapture.Profile) {
+		tasks = append(tasks, learnTasks(aURL, aCapture)...)
+	}
+
+	return append(tasks,
+		chromedp.FullScreenshot(aResult, aOptions.ImageQuality),
+	)
+} // configChrome()
 
 // `cropScale()` Adjusts the image's size to the configured
 // `ImageWidth`/`ImageHeight` values.
 //
 // Parameters:
+//   - `aOptions`: The options to use.
 //   - `aImgData`: The raw image data to cropScale.
 //
 // Returns:
 //   - `image.Image`: The image with adjusted image dimensions.
-func cropScale(aImgData image.Image) image.Image {
+func cropScale(aOptions *TScreenshotParams, aImgData image.Image) image.Image {
 	bounds := aImgData.Bounds()
 	doCrop := false
 	doMagnify := false
 	size := bounds.Size()
-	xIsBigger := (0 < ssOptions.ImageWidth) && (size.X > ssOptions.ImageWidth)
-	yIsBigger := (0 < ssOptions.ImageHeight) && (size.Y > ssOptions.ImageHeight)
+	xIsBigger := (0 < aOptions.ImageWidth) && (size.X > aOptions.ImageWidth)
+	yIsBigger := (0 < aOptions.ImageHeight) && (size.Y > aOptions.ImageHeight)
 
 	if xIsBigger {
 		doCrop = true
-	} else if size.X < ssOptions.ImageWidth {
+	} else if size.X < aOptions.ImageWidth {
 		doMagnify = true
 	}
 	if yIsBigger {
 		doCrop = true
-	} else if size.Y < ssOptions.ImageHeight {
+	} else if size.Y < aOptions.ImageHeight {
 		doMagnify = true
 	}
 
@@ -545,7 +995,7 @@ func cropScale(aImgData image.Image) image.Image {
 
 		if yIsBigger {
 			if xIsBigger { // Both, width and height, are too big.
-				result := image.NewRGBA(image.Rect(0, 0, ssOptions.ImageWidth, ssOptions.ImageHeight))
+				result := image.NewRGBA(image.Rect(0, 0, aOptions.ImageWidth, aOptions.ImageHeight))
 
 				// Perform the actual shrinking:
 				draw.BiLinear.Scale(result, result.Rect,
@@ -558,13 +1008,13 @@ func cropScale(aImgData image.Image) image.Image {
 			// our wanted/configured height.
 			return aImgData.(interface {
 				SubImage(aRect image.Rectangle) image.Image
-			}).SubImage(image.Rect(0, 0, size.X, ssOptions.ImageHeight))
+			}).SubImage(image.Rect(0, 0, size.X, aOptions.ImageHeight))
 		}
 
 		if xIsBigger {
 			return aImgData.(interface {
 				SubImage(aRect image.Rectangle) image.Image
-			}).SubImage(image.Rect(0, 0, ssOptions.ImageWidth, size.Y))
+			}).SubImage(image.Rect(0, 0, aOptions.ImageWidth, size.Y))
 		}
 		// No `else` branch here because we get in this branch only
 		// if either `xIsBigger` or `yIsBigger` (or both) are `true`
@@ -574,7 +1024,7 @@ func cropScale(aImgData image.Image) image.Image {
 	if doMagnify {
 		// Set the configured size:
 		result := image.NewRGBA(image.Rect(0, 0,
-			ssOptions.ImageWidth, ssOptions.ImageHeight))
+			aOptions.ImageWidth, aOptions.ImageHeight))
 
 		// Do the actual enlarging:
 		draw.BiLinear.Scale(result, result.Rect, aImgData,
@@ -586,19 +1036,44 @@ func cropScale(aImgData image.Image) image.Image {
 	return aImgData // unmodified image
 } // cropScale()
 
+// `encodeImage()` returns `aImage` encoded in the configured [ImageType].
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aImage`: The image to encode.
+//
+// Returns:
+//   - `[]byte`: The encoded image data.
+func encodeImage(aOptions *TScreenshotParams, aImage image.Image) []byte {
+	var buffer bytes.Buffer
+
+	if 100 == aOptions.ImageQuality { // 'png' format
+		_ = png.Encode(&buffer, aImage)
+	} else { // 'jpeg' format
+		opts := jpeg.Options{Quality: aOptions.ImageQuality}
+		_ = jpeg.Encode(&buffer, aImage, &opts)
+	}
+
+	return buffer.Bytes()
+} // encodeImage()
+
 // `exists()` returns whether there's an image file already existing.
 //
 // This function uses the `ImageAge()` value to determine whether
 // an already existing local file is considered to be too old.
+// An error page saved in [ErrorPageTTL] mode expires after [ErrorTTL]
+// minutes instead.
 //
-// Files empty or smaller than 4KB are ignored.
+// Files which can't be decoded or are blank (see [SetBlankRatio])
+// are ignored.
 //
 // Parameters:
+//   - `aOptions`: The options to use.
 //   - `aFilename`: The name of the file to check.
 //
 // Returns:
 //   - `bool`: Whether `aFilename` exists.
-func exists(aFilename string) bool {
+func exists(aOptions *TScreenshotParams, aFilename string) bool {
 	if aFilename = strings.TrimSpace(aFilename); 0 == len(aFilename) {
 		return false
 	}
@@ -613,22 +1088,24 @@ func exists(aFilename string) bool {
 		return true
 	}
 
-	if 4096 > fi.Size() {
-		// Empty and small (i.e. `<10KB`) files are ignored.
-		// File sizes smaller than ~10KB indicate some kind of error
-		// during retrieval of the web page or rendering it.
-		// Valid preview images take approximately between 10 up to
-		// ~1MB depending on the respective web page (e.g. number
-		// and size of embedded images).
+	if aOptions.ImageOverwrite {
 		return false
 	}
 
-	if ssOptions.ImageOverwrite {
+	if !validImage(aOptions, aFilename) {
+		// Broken and blank images indicate some kind of error
+		// during retrieval of the web page or rendering it.
 		return false
 	}
 
-	if 0 < ssOptions.ImageAge {
-		maxTime := fi.ModTime().Add(time.Duration(ssOptions.ImageAge) * time.Hour)
+	if stored := readSidecar(aFilename); (nil != stored) && isErrorStatus(stored.Status) {
+		// An error page saved in `ErrorPageTTL` mode:
+		maxTime := fi.ModTime().Add(time.Duration(aOptions.ErrorTTL) * time.Minute)
+		return time.Now().Before(maxTime)
+	}
+
+	if 0 < aOptions.ImageAge {
+		maxTime := fi.ModTime().Add(time.Duration(aOptions.ImageAge) * time.Hour)
 		return time.Now().Before(maxTime)
 	}
 
@@ -656,49 +1133,94 @@ func fileExt(aURL string) string {
 //
 // Parameters:
 //   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
 //   - `aURL`: The remote URL to be handled.
+//   - `aCapture`: The capture result to receive processing details.
 //
 // Returns:
 //   - `[]byte`: The properly encoded image data.
 //   - `error`: A possible processing error.
-func generateImage(aContext context.Context, aURL string) (rImage []byte, rErr error) {
+func generateImage(aContext context.Context, aOptions *TScreenshotParams, aURL string, aCapture *TCaptureResult) ([]byte, error) {
 	var rawData []byte
 
-	ctx, cancel := chromedp.NewContext(aContext,
-		chromedp.WithLogf(log.Printf),
-		// chromedp.WithRunnerOptions(runner.Flag("ignore-certificate-errors", "1")),
+	return renderImage(aContext, aOptions, aURL, configChrome(aOptions, aURL, &rawData, aCapture), &rawData)
+} // generateImage()
+
+// `localFile()` returns the local path/file of `aURL` if it uses
+// the `file://` scheme.
+//
+// Parameters:
+//   - `aURL`: The URL to check.
+//
+// Returns:
+//   - `string`: The local path/file addressed by `aURL`.
+//   - `bool`: Whether `aURL` addresses a local file.
+func localFile(aURL string) (string, bool) {
+	URL, err := url.Parse(aURL)
+	if (nil != err) || ("file" != URL.Scheme) || (0 == len(URL.Path)) {
+		return "", false
+	}
+
+	return filepath.Clean(URL.Path), true
+} // localFile()
+
+// `previewImage()` creates the preview image of `aURL` from the source(s)
+// determined by the [PreviewSource] option.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aURL`: The remote URL to be handled.
+//   - `aCapture`: The capture result to receive processing details.
+//
+// Returns:
+//   - `[]byte`: The properly encoded image data.
+//   - `string`: The source actually used for the image.
+//   - `error`: A possible processing error.
+func previewImage(aContext context.Context, aOptions *TScreenshotParams, aURL string, aCapture *TCaptureResult) ([]byte, string, error) {
+	var (
+		err, err2 error
+		imageData []byte
 	)
 
-	defer func() {
-		// `chromedp.FullScreenshot()` might panic :-((
-		if r := recover(); nil != r {
-			if nil == rErr {
-				rErr = errors.New(ssLibName +
-					": error reading '" + aURL + "'")
-			}
-			log.Println(ssLibName, rErr)
+	switch aOptions.PreviewSource {
+	case PreviewOGImage:
+		imageData, err = ogImage(aContext, aOptions, aURL)
+		return imageData, SourceOGImage, err
+
+	case PreviewOGImageFirst:
+		if imageData, err = ogImage(aContext, aOptions, aURL); nil == err {
+			return imageData, SourceOGImage, nil
+		}
+		if imageData, err2 = generateImage(aContext, aOptions, aURL, aCapture); nil == err2 {
+			return imageData, SourceScreenshot, nil
 		}
-		cancel()
-	}()
 
-	// Capture the entire browser viewport
-	if rErr = chromedp.Run(ctx, configChrome(aURL, &rawData)); nil != rawData {
-		if nil != rErr {
-			log.Println(ssLibName, ":", aURL, ImageType(), ssOptions.ImageQuality, rErr)
+	case PreviewScreenshotFirst:
+		if imageData, err = generateImage(aContext, aOptions, aURL, aCapture); nil == err {
+			return imageData, SourceScreenshot, nil
 		}
-		if rImage = cleanupOutput(rawData); 4096 < len(rImage) {
-			rErr = nil
+		if errors.Is(err, errNeedsJS) {
+			return nil, "", err // `Capture()` tries again with JavaScript
 		}
+		if imageData, err2 = ogImage(aContext, aOptions, aURL); nil == err2 {
+			return imageData, SourceOGImage, nil
+		}
+
+	default:
+		imageData, err = generateImage(aContext, aOptions, aURL, aCapture)
+		return imageData, SourceScreenshot, err
 	}
 
-	return
-} // generateImage()
+	return nil, "", errors.Join(err, err2)
+} // previewImage()
 
 // `readListFile()` reads the named text file and returns its lines
 // as a list of strings.
 //
 // NOTE: The resulting list may contain empty lines.
-// All lines in the list are lowercased and trimmed.
+// All lines in the list are trimmed and – except for regular
+// expressions – lowercased.
 //
 // Parameters:
 //   - `aFilename`: The name of the file to read.
@@ -733,8 +1255,11 @@ reStart:
 
 		// Make sure there are no "\t" or "\r" left and
 		// everything is in lowercase letters which is
-		// expected by `chk4()`/`containsHost()`.
-		line = strings.ToLower(strings.TrimSpace(line))
+		// expected by the host patterns.
+		// Regular expressions are case-sensitive (e.g. `\d` vs. `\D`).
+		if line = strings.TrimSpace(line); !strings.HasPrefix(line, "re:") {
+			line = strings.ToLower(line)
+		}
 		if (0 == len(line)) || (`#` == line[0:1]) {
 			rList = removeIndex(rList, idx)
 			goto reStart // restart the loop
@@ -793,15 +1318,80 @@ func removeIndex(aList sort.StringSlice, aIndex int) sort.StringSlice {
 	return append(append(result, aList[:aIndex]...), aList[aIndex+1:]...)
 } // removeIndex()
 
+// `renderImage()` runs `aTasks` in a new browser tab and returns
+// the resulting image data and any error encountered.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aOptions`: The options to use.
+//   - `aName`: The name (URL) of the processed page used in error messages.
+//   - `aTasks`: The browser actions to perform.
+//   - `aRawData`: The data structure receiving the screenshot by `aTasks`.
+//
+// Returns:
+//   - `[]byte`: The properly encoded image data.
+//   - `error`: A possible processing error.
+func renderImage(aContext context.Context, aOptions *TScreenshotParams, aName string, aTasks chromedp.Tasks, aRawData *[]byte) (rImage []byte, rErr error) {
+	ctx, cancel, err := browserContext(aContext, aName)
+	if nil != err {
+		return nil, err
+	}
+
+	defer func() {
+		// `chromedp.FullScreenshot()` might panic :-((
+		if r := recover(); nil != r {
+			if nil == rErr {
+				rErr = errors.New(ssLibName +
+					": error reading '" + aName + "'")
+			}
+			log.Println(ssLibName, rErr)
+		}
+		cancel()
+		if (nil != rErr) && ssOptions.BrowserReuse {
+			// Restart a crashed or hanging browser:
+			rErr = ssSupervisor.check(ctx, rErr)
+		}
+	}()
+
+	// Capture the entire browser viewport
+	if rErr = chromedp.Run(ctx, aTasks); nil != *aRawData {
+		if nil != rErr {
+			log.Println(ssLibName, ":", aName, ssImageTypes[100 > aOptions.ImageQuality], aOptions.ImageQuality, rErr)
+		}
+		if rImage = cleanupOutput(aOptions, *aRawData); 0 < len(rImage) {
+			rErr = nil
+		} else if nil == rErr {
+			rErr = fmt.Errorf("%w: '%s'", ErrBlankImage, aName)
+		}
+	}
+
+	return
+} // renderImage()
+
 // `sanitise()` returns `aURL` with all non alpha/digits removed.
 // The resulting string can then be used as the screenshot's file name.
 //
+// The URL's host is normalised before (see `normaliseHost()`) and
+// the scheme's default port removed, so e.g. `https://Bücher.de:443/`
+// and `https://xn--bcher-kva.de/` use the same file.
+//
 // Parameters:
 //   - `aURL`: The URL to sanitise.
 //
 // Returns:
 //   - `string`: The complete path/file.
 func sanitise(aURL string) string {
+	if URL, err := url.Parse(aURL); (nil == err) && (0 < len(URL.Host)) {
+		host := normaliseHost(URL.Hostname())
+		if port := URL.Port(); (0 < len(port)) && (defaultPort(URL.Scheme) != port) {
+			host += ":" + port
+		}
+		// Replace just the host leaving the rest of `aURL` untouched:
+		if idx := strings.Index(aURL, URL.Host); 0 <= idx {
+			aURL = aURL[:idx] + host + aURL[idx+len(URL.Host):]
+		}
+	}
+
 	return ssReplaceNonAlphasRE.ReplaceAllLiteralString(aURL, ``)
 } // sanitise()
 
@@ -861,6 +1451,28 @@ func stat(aFilename string) (string, bool) {
 	return "", false
 } // stat()
 
+// `waitTime()` returns the time to wait for receiving and rendering
+// a web page (see [SetWaitTime]).
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aEnableJS`: Whether JavaScript is active in the browser.
+//
+// Returns:
+//   - `time.Duration`: The time to wait before taking the screenshot.
+func waitTime(aOptions *TScreenshotParams, aEnableJS bool) time.Duration {
+	if 0 < aOptions.WaitTime {
+		return time.Duration(aOptions.WaitTime) * time.Millisecond
+	}
+
+	result := time.Second << 1 // two seconds
+	if aEnableJS {
+		result <<= 1 // four seconds
+	}
+
+	return result
+} // waitTime()
+
 // 'writeFile()' stores the given image data to a file, returning an
 // error in case of problems.
 //
@@ -953,6 +1565,7 @@ func AvoidJSfile() string {
 // hosts/domains where to avoid running JavaScript.
 //
 // NOTE: This value is used only if the `JavaScript()` property is `true`.
+// The file is watched for changes (see [SetReloadFunc]).
 // An invalid filename disables the feature.
 //
 // Parameters:
@@ -961,6 +1574,101 @@ func SetAvoidJSfile(aFilename string) {
 	ssOptions.HostsAvoidJSfile = setHosts4JS(aFilename, defaultHostsAvoidJS)
 } // SetHostsAvoidJS()
 
+// `Capture()` generates an image of `aURL` and stores it in [ImageDir],
+// returning a description of the saved image or an error in case of
+// problems.
+//
+// Depending on the [PreviewSource] option the image is either a
+// screenshot of the rendered web page or the page's `og:image`;
+// either way it's adjusted to the configured [ImageWidth] and
+// [ImageHeight] and saved in the configured [ImageType].
+//
+// In case the [ImageAge] or [AcceptOther] properties determine that the
+// requested screenshot image already exists this function does not in
+// fact create another screenshot but returns that existing filename.
+// See also the comments to the [SetAcceptOther] function.
+//
+// A capture failing with a transient error is retried according to the
+// [RetryAttempts], [RetryDelay] and [RetryOn] settings.
+//
+// The options of the capture profiles matching `aURL` are applied
+// (see [SetProfilesFile]).
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `*TCaptureResult`: The description of the saved image.
+//   - `error`: A possible error during creation of the screenshot image.
+func Capture(aURL string) (*TCaptureResult, error) {
+	if 0 == len(ssOptions.ImageDir) {
+		return nil, errors.New(ssLibName + ": property 'ImageDir' is empty")
+	}
+	// The matching profiles' options apply to this capture only:
+	options, profile := captureOptions(aURL)
+
+	ext := ssImageTypes[100 > options.ImageQuality]
+	// Look up the cached image using the URL's canonical form:
+	sanitised := sanitise(canonical(aURL))
+	result := &TCaptureResult{
+		Filename: sanitised + `.` + ext,
+		Profile:  profile,
+		URL:      aURL,
+	}
+	fName := filepath.Join(ssOptions.ImageDir, result.Filename)
+	// Check whether we've already got an image file
+	// so we might avoid additional network traffic:
+	if exists(options, fName) {
+		return cached(fName, result), nil
+	}
+
+	if options.AcceptOther {
+		switch ext {
+		case `jpeg`:
+			result.Filename = sanitised + `.png`
+			if fName2 := filepath.Join(ssOptions.ImageDir, result.Filename); exists(options, fName2) {
+				return cached(fName2, result), nil
+			}
+
+		case `png`:
+			result.Filename = sanitised + `.jpeg`
+			if fName2 := filepath.Join(ssOptions.ImageDir, result.Filename); exists(options, fName2) {
+				return cached(fName2, result), nil
+			}
+		}
+		result.Filename = sanitised + `.` + ext
+	}
+
+	// Retry captures failing with transient errors:
+	base := *result
+	attempts, err := withRetry(options, aURL, func() error {
+		*result = base
+		err := capture(options, aURL, sanitised, result)
+		if errors.Is(err, errNeedsJS) && ssLearned.learn(aURL) {
+			// The page needs JavaScript (see `SetLearnJSfile()`):
+			*result = base
+			result.LearnedJS = true
+			err = capture(options, aURL, sanitised, result)
+		}
+
+		return err
+	})
+	if nil != err {
+		return nil, err
+	}
+	result.Attempts = attempts
+
+	// An error page's sidecar is needed to apply the `ErrorTTL`:
+	if options.Sidecar ||
+		((ErrorPageTTL == options.ErrorPages) && isErrorStatus(result.Status)) {
+		if err = writeSidecar(filepath.Join(ssOptions.ImageDir, result.Filename), result); nil != err {
+			log.Println(ssLibName, err)
+		}
+	}
+
+	return result, nil
+} // Capture()
+
 // `CertErrors()` returns whether to skip sites with certificate errors;
 // defaults to `false` which in consequence ignores such errors.
 //
@@ -1000,10 +1708,8 @@ func SetCookies(doAllow bool) {
 // `CreateImage()` generates an image of `aURL` and stores it in [ImageDir],
 // returning the file name of the saved image or an error in case of problems.
 //
-// In case the [ImageAge] or [AcceptOther] properties determine that the
-// requested screenshot image already exists this function does not in
-// fact create another screenshot but returns that existing filename.
-// See also the comments to the [SetAcceptOther] function.
+// This is a convenience wrapper around [Capture] for callers which
+// are interested only in the image's file name.
 //
 // Parameters:
 //   - `aURL`: The address of the web page to process.
@@ -1012,108 +1718,12 @@ func SetCookies(doAllow bool) {
 //   - `string`: The file name of the saved image.
 //   - `error`: A possible error during creation of the screenshot image.
 func CreateImage(aURL string) (string, error) {
-	if 0 == len(ssOptions.ImageDir) {
-		return "", errors.New(ssLibName + ": property 'ImageDir' is empty")
-	}
-
-	ext := ssImageTypes[100 > ssOptions.ImageQuality]
-	sanitised := sanitise(aURL)
-	result := sanitised + `.` + ext
-	fName := filepath.Join(ssOptions.ImageDir, result)
-	// Check whether we've already got an image file
-	// so we might avoid additional network traffic:
-	if exists(fName) {
-		return result, nil
-	}
-
-	if ssOptions.AcceptOther {
-		switch ext {
-		case `jpeg`:
-			result2 := sanitised + `.png`
-			if fName2 := filepath.Join(ssOptions.ImageDir, result2); exists(fName2) {
-				return result2, nil
-			}
-
-		case `png`:
-			result2 := sanitised + `.jpeg`
-			if fName2 := filepath.Join(ssOptions.ImageDir, result2); exists(fName2) {
-				return result2, nil
-			}
-		}
-	}
-
-	var (
-		// Declare variables here so we can use them in different
-		// contexts/closures below (and it eases debugging).
-		cancel    context.CancelFunc
-		ctx       context.Context
-		err       error
-		imageData []byte
-		response  *http.Response
-	)
-
-	ctx, cancel = context.WithTimeout(context.Background(), time.Duration(ssOptions.MaxProcessTime)*time.Second)
-	defer func() {
-		if r := recover(); nil != r {
-			// Timing problems or invalid site data might indirectly
-			// cause the image generation to panic.
-			log.Println(ssLibName, err)
-		}
-		cancel()
-	}()
-
-	// Exclude certain filetypes from preview generation:
-	ext = strings.ToLower(fileExt(aURL))
-	switch ext {
-	case ".amr", ".arj", ".avi", ".azw3",
-		".bak", ".bibtex", ".bz2",
-		".cfg", ".com", ".conf", ".csv",
-		".db", ".deb", ".doc", ".docx", ".dia",
-		".epub", ".exe", ".flv", ".gz",
-		".ics", ".iso", ".jar", ".json",
-		".md", ".mobi", ".mp3", ".mp4", ".mpeg",
-		".odf", ".odg", ".odp", ".ods", ".odt", ".otf", ".oxt",
-		".pas", ".pdf", ".ppd", ".ppt", ".pptx",
-		".rip", ".rpm", ".spk", ".sxg", ".sxw",
-		".ttf", ".vbox", ".vmdk", ".vcs", ".wav",
-		".xls", ".xpi", ".xsl", ".zip":
-		return "", errors.New(ssLibName +
-			": excluded filename extension '" + ext + "'")
-
-	case ".gif", ".jpeg", ".jpg", ".png", ".svg":
-		if response, err = http.Get(aURL); /* #nosec G107 */ nil != err {
-			return "", err
-		}
-		defer response.Body.Close()
-		result = sanitised + ext
-		fName = filepath.Join(ssOptions.ImageDir, result)
-
-	default:
-		if imageData, err = generateImage(ctx, aURL); nil != err {
-			return "", err
-		}
-
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err() // Canceled? TimeOut?
-
-		default:
-			break // still within our allocated time frame
-		}
-	}
-
-	if (0 == len(imageData)) && (nil == response) {
-		return "", errors.New(ssLibName + ": no data received for '" +
-			fName + "'")
-	}
-
-	if err = writeFile(fName, imageData, response); nil != err {
-		// some problem during attempt to save image to disk
+	result, err := Capture(aURL)
+	if nil != err {
 		return "", err
 	}
 
-	// Everything went well it seems …
-	return result, nil
+	return result.Filename, nil
 } // CreateImage()
 
 // `ImageAge()` returns the maximum age (in hours) of the locally stored
@@ -1400,6 +2010,7 @@ func NeedJSfile() string {
 // hosts/domains requiring JavaScript to be active/working.
 //
 // NOTE: This value is used only if the [JavaScript] option is set `false`.
+// The file is watched for changes (see [SetReloadFunc]).
 // An invalid filename disables the feature.
 //
 // Parameters:
@@ -1410,6 +2021,9 @@ func SetNeedJSfile(aFilename string) {
 
 // `PathFile()` returns the complete local path/file of `aURL`.
 //
+// The filename is derived from the canonical form of `aURL` (see
+// [SetCanonicalParams] and [SetCanonicalScheme]).
+//
 // NOTE: This function does not check whether the image file for `aURL`
 // actually exists in the local filesystem but just reports the default
 // path-/filename computed by string operations.
@@ -1421,7 +2035,7 @@ func SetNeedJSfile(aFilename string) {
 //   - `string`: The path/file of the screenshot of `aURL`.
 func PathFile(aURL string) string {
 	return filepath.Join(ssOptions.ImageDir,
-		sanitise(aURL)+`.`+ssImageTypes[100 > ssOptions.ImageQuality])
+		sanitise(canonical(aURL))+`.`+ssImageTypes[100 > ssOptions.ImageQuality])
 } // PathFile()
 
 // `Platform()` returns the text the JS `navigator.platform` should return.
@@ -1448,6 +2062,37 @@ func SetPlatform(aPlatform string) {
 	}
 } // SetPlatform()
 
+// `PreviewSource()` returns where the preview image of a web page
+// is taken from.
+//
+// Returns:
+//   - `TPreviewSource`: The currently configured preview source.
+func PreviewSource() TPreviewSource {
+	return ssOptions.PreviewSource
+} // PreviewSource()
+
+// `SetPreviewSource()` determines where the preview image of a web
+// page is taken from:
+//
+//   - [PreviewScreenshot]: a screenshot of the rendered page (default),
+//   - [PreviewOGImage]: the page's `og:image`,
+//   - [PreviewOGImageFirst]: the `og:image` or – if there's none –
+//     a screenshot,
+//   - [PreviewScreenshotFirst]: a screenshot or – if that fails –
+//     the `og:image`.
+//
+// An invalid value resets this property to [PreviewScreenshot].
+//
+// Parameters:
+//   - `aSource`: The preview source to use.
+func SetPreviewSource(aSource TPreviewSource) {
+	if (PreviewScreenshot <= aSource) && (PreviewScreenshotFirst >= aSource) {
+		ssOptions.PreviewSource = aSource
+	} else {
+		ssOptions.PreviewSource = PreviewScreenshot
+	}
+} // SetPreviewSource()
+
 // `ReadWaitTime()` returns the number of minutes to wait before an Avoid/Need
 // hosts file is re-read.
 //
@@ -1462,6 +2107,10 @@ func ReadWaitTime() int {
 // `SetReadWaitTime()` sets the number of minutes to wait before an Avoid/Need
 // hosts file is re-read.
 //
+// NOTE: The Avoid/Need hosts files are watched for changes and re-read
+// immediately (see [SetReloadFunc]); this setting applies to them only
+// if the file system doesn't support watching.
+//
 // Usually you'll want this property at its default value (`1`, one)
 // which seems to be a reasonable compromise between batch processing
 // (i.e. looping through a list of URLs to process) and mitigation of
@@ -1500,6 +2149,27 @@ func SetScrollbars(aScrollbar bool) {
 	ssOptions.Scrollbars = aScrollbar
 } // SetScrollbars()
 
+// `Sidecar()` returns whether a JSON file describing the capture
+// result (see [TCaptureResult]) is written next to each image file.
+//
+// Returns:
+//   - `bool`: Whether sidecar files are written.
+func Sidecar() bool {
+	return ssOptions.Sidecar
+} // Sidecar()
+
+// `SetSidecar()` determines whether a JSON file describing the capture
+// result (see [TCaptureResult]) is written next to each image file.
+//
+// The sidecar file is named like the image file with an additional
+// `.json` extension; it's default is `false`.
+//
+// Parameters:
+//   - `doWrite`: Whether to write sidecar files.
+func SetSidecar(doWrite bool) {
+	ssOptions.Sidecar = doWrite
+} // SetSidecar()
+
 // `UserAgent()` returns the current `User Agent` setting.
 //
 // NOTE: This value is used only if the `JavaScript()` option is set `true`.
@@ -87,66 +87,20 @@ func Test_chk4(t *testing.T) {
 	}
 } // Test_chk4()
 
-func Test_containsHost(t *testing.T) {
-	type tArgs struct {
-		aNeedle   string
-		aHaystack *sort.StringSlice
-	}
-	//
-	n1 := ""
-	h1 := &sort.StringSlice{}
-	//
-	n2 := "www.example.org"
-	h2 := &sort.StringSlice{
-		"",
-		"# test list",
-		"",
-		"gibbet.nich",
-		"example.com",
-		"",
-		"# _EoF_",
-	} // h2
-	//
-	n3 := "www.example.com"
-	h3 := h2
-	//
-	n4 := "example.com"
-	h4 := &sort.StringSlice{
-		"",
-		"# test list",
-		".example.com",
-		"",
-		"# _EoF_",
-	} // h4
-
-	tests := []struct {
-		name string
-		args tArgs
-		want bool
-	}{
-		{" 1", tArgs{n1, h1}, false},
-		{" 2", tArgs{n2, h2}, false},
-		{" 3", tArgs{n3, h3}, true},
-		{" 4", tArgs{n4, h4}, false},
-		// TODO: Add test cases.
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := containsHost(tt.args.aNeedle, tt.args.aHaystack); got != tt.want {
-				t.Errorf("%q: listContains() = %v, want %v",
-					tt.name, got, tt.want)
-			}
-		})
-	}
-} // Test_containsHost()
-
 func Test_exists(t *testing.T) {
-	f1 := `/ is not there`   // root dir is not writeable
-	f2 := ``                 // invalid (empty) filename
-	f3 := `/home`            // is directory, i.e. irregular
-	f4 := `/etc/cron.d/`     // (dito)
-	f5 := `/etc/crontab`     // exists but too small
-	f6 := `/etc/ld.so.cache` // exists and big enough
+	f1 := `/ is not there` // root dir is not writeable
+	f2 := ``               // invalid (empty) filename
+	f3 := `/home`          // is directory, i.e. irregular
+	f4 := `/etc/cron.d/`   // (dito)
+	f5 := `/etc/crontab`   // exists but isn't an image
+	f6 := `./Crash_Test_Dummies.blank.png`
+	f7 := `./Crash_Test_Dummies.valid.png`
+	defer func() {
+		_ = os.Remove(f6)
+		_ = os.Remove(f7)
+	}()
+	_ = writeFile(f6, testImage(false), nil) // exists but blank
+	_ = writeFile(f7, testImage(true), nil)  // exists and valid
 
 	tests := []struct {
 		name      string
@@ -158,12 +112,13 @@ func Test_exists(t *testing.T) {
 		{"3", f3, true},
 		{"4", f4, true},
 		{"5", f5, false},
-		{"6", f6, true},
+		{"6", f6, false},
+		{"7", f7, true},
 		// TODO: Add test cases.
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := exists(tt.aFilename); got != tt.want {
+			if got := exists(ssOptions, tt.aFilename); got != tt.want {
 				t.Errorf("%q: exists() = %v, want %v",
 					tt.name, got, tt.want)
 			}
@@ -216,7 +171,7 @@ func Test_generateImage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := generateImage(tt.args.aContext, tt.args.aURL)
+			_, err := generateImage(tt.args.aContext, ssOptions, tt.args.aURL, &TCaptureResult{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("%q: generateImage() error = %v, wantErr %v",
 					tt.name, err, tt.wantErr)
@@ -226,6 +181,35 @@ func Test_generateImage(t *testing.T) {
 	}
 } // Test_generateImage()
 
+func Test_localFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		aURL   string
+		want   string
+		wantOK bool
+	}{
+		{"1", "", "", false},
+		{"2", "https://example.com/image.png", "", false},
+		{"3", "file:///tmp/image.png", "/tmp/image.png", true},
+		{"4", "file:///tmp/../etc/page.html", "/etc/page.html", true},
+		{"5", "file://", "", false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK := localFile(tt.aURL)
+			if got != tt.want {
+				t.Errorf("%q: localFile() got = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if gotOK != tt.wantOK {
+				t.Errorf("%q: localFile() gotOK = %v, want %v",
+					tt.name, gotOK, tt.wantOK)
+			}
+		})
+	}
+} // Test_localFile()
+
 func Test_readListFile(t *testing.T) {
 	const fName = "./Crash_Test_Dummies.lst"
 	list := `
@@ -356,6 +340,10 @@ func Test_sanitise(t *testing.T) {
 		{"1", "http://dev.mwat.de/#main", "httpdevmwatdemain"},
 		{"2", "http://www.gibbet.nich/~matthias/index.html", "httpwwwgibbetnichmatthiasindexhtml"},
 		{"3", "gopher://localhost/a/b/c", "gopherlocalhostabc"},
+		{"4", "https://Bücher.DE./Straße", "httpsxnbcherkvadeStrae"},
+		{"5", "https://xn--bcher-kva.de:443/Straße", "httpsxnbcherkvadeStrae"},
+		{"6", "http://example.com:8080/", "httpexamplecom8080"},
+		{"7", "http://[0:0::1]:80/", "http1"},
 		// TODO: Add test cases.
 	}
 	for _, tt := range tests {
@@ -482,28 +470,28 @@ func TestCreateImage(t *testing.T) {
 	fileExt := ImageType()
 
 	u1 := "https://www.buzzfeednews.com/article/alexkantrowitz/how-the-retweet-ruined-the-internet"
-	w1 := sanitise(u1) + "." + fileExt
+	w1 := sanitise(canonical(u1)) + "." + fileExt
 	u2 := "https://github.com/mwat56/screenshot"
-	w2 := sanitise(u2) + "." + fileExt
+	w2 := sanitise(canonical(u2)) + "." + fileExt
 	u3 := "https://www.eff.org/"
-	w3 := sanitise(u3) + "." + fileExt
+	w3 := sanitise(canonical(u3)) + "." + fileExt
 	u4 := "https://www.ohchr.org/Documents/Issues/Opinion/Legislation/OL-DEU-1-2017.pdf"
 	u5 := "https://www.startpage.com/do/mypage.pl?prfe=11742c69614c5050a145b9bdfad5f008146ec505c5c35adf413b6739b701f24f929c4c53c5685edf58d38e108cf7c95f86bb0436a1b232f8dbddd145af3b1808fba2c6679ec1fdea5462bd4c31b364e98f"
-	w5 := sanitise(u5) + "." + fileExt
+	w5 := sanitise(canonical(u5)) + "." + fileExt
 	u6 := "https://uncommongroundmedia.com/thats-not-what-i-meant-brain-damage-communication-part-i-%EF%BB%BF-dr-em/"
-	w6 := sanitise(u6) + "." + fileExt
+	w6 := sanitise(canonical(u6)) + "." + fileExt
 	u7 := "https://thehackernews.com/2017/10/kaspersky-nsa-russian-hackers.html#articlebody"
-	w7 := sanitise(u7) + "." + fileExt
+	w7 := sanitise(canonical(u7)) + "." + fileExt
 	// u8 := "http://www.mwat.de/CSS/mwBack-526x841.gif"
-	// w8 := sanitise(u8) + ".gif"
+	// w8 := sanitise(canonical(u8)) + ".gif"
 	u9 := "https://www.youtube.com/watch?v=tvcwMcGWf-w"
-	w9 := sanitise(u9) + "." + fileExt
+	w9 := sanitise(canonical(u9)) + "." + fileExt
 	u10 := "https://twitter.com/seerutkchawla/status/1337231261430132738"
-	w10 := sanitise(u10) + "." + fileExt
+	w10 := sanitise(canonical(u10)) + "." + fileExt
 	u11 := "https://www.facebook.com/robertjsawyer/posts/10155509139641013#contentArea"
-	w11 := sanitise(u11) + "." + fileExt
+	w11 := sanitise(canonical(u11)) + "." + fileExt
 	u12 := "https://diekolumnisten.de/2018/12/28/deutschland-verrecke/#post-19938"
-	w12 := sanitise(u12) + "." + fileExt
+	w12 := sanitise(canonical(u12)) + "." + fileExt
 
 	prep := func() {
 		_ = os.Remove(PathFile(u1))
@@ -612,10 +600,29 @@ func TestSetHostsNeedJS(t *testing.T) {
 func TestString(t *testing.T) {
 	setupScreenshot()
 
-	w1 := `AcceptOther:	true
+	w1 := `AcceptLanguage:	''
+AcceptOther:	true
+BlankRatio:	99
+BlankTolerance:	8
+BlockFile:	''
+BrowserMaxRSS:	0
+BrowserRecycle:	0
+BrowserReuse:	false
+CanonicalParams:	'fbclid,gclid,utm_*'
+CanonicalScheme:	false
 CertErrors:	false
+ChromeFlags:	''
+ConsentFile:	'/home/matthias/devel/Go/src/github.com/mwat56/screenshot/consentrules.list'
+CookieFile:	''
 Cookies:	false
+CredentialsFile:	''
+ErrorPages:	'fail'
+ErrorTTL:	60
+ExecPath:	''
+Headless:	true
+HideSelectors:	''
 HostsAvoidJSfile:	'/home/matthias/devel/Go/src/github.com/mwat56/screenshot/hostsavoidjs.list'
+HostsLearnJSfile:	''
 HostsNeedJSfile:	'/home/matthias/devel/Go/src/github.com/mwat56/screenshot/hostsneedjs.list'
 ImageAge:	0
 ImageDir:	'/tmp'
@@ -626,10 +633,27 @@ ImageScale:	0.99
 ImageWidth:	896
 JavaScript:	false
 MaxProcessTime:	24
+MimePolicy:	''
 Mobile:	false
 Platform:	'Linux x86_64'
+PreviewSource:	'screenshot'
+ProfileDir:	''
+ProfileMode:	'ephemeral'
+ProfilesFile:	''
+Proxy:	''
+ProxyBypass:	''
+ProxyFile:	''
+RemoteURL:	''
+RetryAttempts:	1
+RetryDelay:	500
+RetryOn:	'browser,network,server,timeout'
+ScriptsFile:	''
 Scrollbars:	true
+Sidecar:	false
+StylesFile:	''
 UserAgent:	'Mozilla/5.0 (X11; Linux x86_64; rv:80.0) Gecko/20100101 Firefox/80.0'
+WaitSelector:	''
+WaitTime:	0
 `
 	tests := []struct {
 		name string
@@ -0,0 +1,221 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Don't save error pages but return an error (default).
+	ErrorPageFail TErrorPageMode = iota
+
+	// Save error pages under a separate name.
+	ErrorPageSeparate
+
+	// Save error pages under their regular name but let them
+	// expire after [ErrorTTL] minutes.
+	ErrorPageTTL
+)
+
+const (
+	// Suffix of the image file name of separately saved error pages:
+	errorSuffix = `_error`
+)
+
+type (
+	// TErrorPageMode determines how the screenshot of a web page is
+	// handled whose server answered with an HTTP error status.
+	TErrorPageMode int
+)
+
+// `String()` returns the name of the error page mode.
+//
+// Returns:
+//   - `string`: The error page mode's name.
+func (em TErrorPageMode) String() string {
+	switch em {
+	case ErrorPageSeparate:
+		return "separate"
+	case ErrorPageTTL:
+		return "ttl"
+	default:
+		return "fail"
+	}
+} // String()
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `errorFilename()` returns the name of the image file `aFilename`
+// to use for a separately saved error page.
+//
+// Parameters:
+//   - `aFilename`: The regular name of the image file.
+//
+// Returns:
+//   - `string`: The error page's image file name.
+func errorFilename(aFilename string) string {
+	ext := filepath.Ext(aFilename)
+
+	return strings.TrimSuffix(aFilename, ext) + errorSuffix + ext
+} // errorFilename()
+
+// `isErrorStatus()` returns whether `aStatus` is an HTTP error status.
+//
+// Parameters:
+//   - `aStatus`: The HTTP status code to check.
+//
+// Returns:
+//   - `bool`: Whether `aStatus` denotes an error page.
+func isErrorStatus(aStatus int) bool {
+	return http.StatusBadRequest <= aStatus
+} // isErrorStatus()
+
+// `statusError()` returns the error describing the HTTP error status
+// of the web page described by `aCapture`.
+//
+// Parameters:
+//   - `aCapture`: The capture result with the page's status.
+//
+// Returns:
+//   - `error`: The HTTP status error.
+func statusError(aCapture *TCaptureResult) error {
+	return &TStatusError{
+		Status: strings.TrimSpace(strconv.Itoa(aCapture.Status) + " " +
+			http.StatusText(aCapture.Status)),
+		StatusCode: aCapture.Status,
+		URL:        aCapture.URL,
+	}
+} // statusError()
+
+// `statusTasks()` returns the browser actions recording the HTTP
+// status and the final URL (after redirects) of the main document.
+//
+// Parameters:
+//   - `aCapture`: The capture result to receive the status.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform before navigation.
+//   - `chromedp.Tasks`: The actions to perform after loading the page.
+func statusTasks(aCapture *TCaptureResult) (rBefore, rAfter chromedp.Tasks) {
+	var (
+		finalURL string
+		mtx      sync.Mutex
+		status   int
+	)
+
+	rBefore = chromedp.Tasks{
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			c := chromedp.FromContext(aContext)
+			if (nil == c) || (nil == c.Target) {
+				return nil
+			}
+			// The main frame's ID equals the target's ID:
+			mainFrame := cdp.FrameID(c.Target.TargetID)
+
+			chromedp.ListenTarget(aContext, func(aEvent any) {
+				ev, ok := aEvent.(*network.EventResponseReceived)
+				if !ok || (network.ResourceTypeDocument != ev.Type) ||
+					(mainFrame != ev.FrameID) || (nil == ev.Response) {
+					return
+				}
+				mtx.Lock()
+				finalURL, status = ev.Response.URL, int(ev.Response.Status)
+				mtx.Unlock()
+			})
+
+			return nil
+		}),
+	}
+
+	rAfter = chromedp.Tasks{
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			mtx.Lock()
+			aCapture.FinalURL, aCapture.Status = finalURL, status
+			mtx.Unlock()
+
+			return nil
+		}),
+	}
+
+	return
+} // statusTasks()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `ErrorPages()` returns how screenshots of HTTP error pages are handled.
+//
+// Returns:
+//   - `TErrorPageMode`: The current error page mode.
+func ErrorPages() TErrorPageMode {
+	return ssOptions.ErrorPages
+} // ErrorPages()
+
+// `SetErrorPages()` configures how the screenshot of a web page is
+// handled whose server answered with an HTTP error status (4xx/5xx),
+// like a "404 Not Found" or a "503 Service Unavailable" maintenance
+// page:
+//
+//   - [ErrorPageFail]: the image isn't saved, and [Capture] returns
+//     a [TStatusError] (default),
+//   - [ErrorPageSeparate]: the image is saved under the regular name
+//     with an `_error` suffix, leaving the regular image untouched,
+//   - [ErrorPageTTL]: the image is saved under its regular name but
+//     is replaced after [ErrorTTL] minutes.
+//
+// Either way the page's HTTP status and final URL are reported in
+// the capture's result.
+//
+// Parameters:
+//   - `aMode`: The error page mode to use.
+func SetErrorPages(aMode TErrorPageMode) {
+	if (ErrorPageFail <= aMode) && (ErrorPageTTL >= aMode) {
+		ssOptions.ErrorPages = aMode
+	} else {
+		ssOptions.ErrorPages = ErrorPageFail
+	}
+} // SetErrorPages()
+
+// `ErrorTTL()` returns the time (in minutes) after which a saved error
+// page gets replaced.
+//
+// Returns:
+//   - `int`: The error pages' time to live.
+func ErrorTTL() int {
+	return ssOptions.ErrorTTL
+} // ErrorTTL()
+
+// `SetErrorTTL()` configures the time (in minutes) after which an
+// error page saved in the [ErrorPageTTL] mode gets replaced.
+//
+// NOTE: To recognise the error page later on its sidecar file is
+// written regardless of the [Sidecar] setting.
+//
+// Parameters:
+//   - `aMinutes`: The error pages' time to live.
+func SetErrorTTL(aMinutes int) {
+	if 1 > aMinutes {
+		aMinutes = 1
+	}
+	ssOptions.ErrorTTL = aMinutes
+} // SetErrorTTL()
+
+/* _EoF_ */
@@ -0,0 +1,64 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"testing"
+)
+
+func Test_errorFilename(t *testing.T) {
+	tests := []struct {
+		name      string
+		aFilename string
+		want      string
+	}{
+		{"1", "examplecom.png", "examplecom_error.png"},
+		{"2", "examplecom.jpeg", "examplecom_error.jpeg"},
+		{"3", "examplecom", "examplecom_error"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorFilename(tt.aFilename); got != tt.want {
+				t.Errorf("%q: errorFilename() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_errorFilename()
+
+func Test_statusError(t *testing.T) {
+	tests := []struct {
+		name     string
+		aCapture *TCaptureResult
+		want     string
+		wantRtry bool
+	}{
+		{"1", &TCaptureResult{Status: 404, URL: "https://example.com/x"},
+			ssLibName + ": 'https://example.com/x' returned 404 Not Found", false},
+		{"2", &TCaptureResult{Status: 503, URL: "https://example.com/"},
+			ssLibName + ": 'https://example.com/' returned 503 Service Unavailable", true},
+		{"3", &TCaptureResult{Status: 599, URL: "https://example.com/"},
+			ssLibName + ": 'https://example.com/' returned 599", true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := statusError(tt.aCapture)
+			if got := err.Error(); got != tt.want {
+				t.Errorf("%q: statusError() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+			if got := (RetryServer == errorClass(err)); got != tt.wantRtry {
+				t.Errorf("%q: errorClass() retryable = %v, want %v",
+					tt.name, got, tt.wantRtry)
+			}
+		})
+	}
+} // Test_statusError()
+
+/* _EoF_ */
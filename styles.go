@@ -0,0 +1,197 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+/*
+The styles file is a host rules file (see `hostrules.go`) with the
+actions
+
+	css  STYLESHEETFILE
+	hide SELECTOR
+
+`css` adds the given user stylesheet to the page, `hide` hides all
+elements matching the given CSS selector (i.e. `display:none`).
+Relative stylesheet filenames are relative to the styles file's
+directory.
+
+Example:
+
+	*            hide  .cookie-banner, #newsletter-modal
+	example.com  hide  header.sticky
+	example.com  css   example.css
+*/
+
+const (
+	// Rule keyword for user stylesheets:
+	actionCSS = `css`
+
+	// Rule keyword for CSS selectors of elements to hide:
+	actionHide = `hide`
+)
+
+var (
+	// The rules of the styles file:
+	ssStyles = tHostRules{
+		kind: RulesStyles,
+	}
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `hideRule()` returns a CSS rule hiding all elements matching
+// `aSelectors`.
+//
+// Parameters:
+//   - `aSelectors`: The CSS selectors of the elements to hide.
+//
+// Returns:
+//   - `string`: The CSS rule or an empty string.
+func hideRule(aSelectors []string) string {
+	if 0 == len(aSelectors) {
+		return ""
+	}
+
+	return strings.Join(aSelectors, ",\n") +
+		" {\n\tdisplay: none !important;\n}\n"
+} // hideRule()
+
+// `styleSheet()` returns the user stylesheet for the host of `aURL`.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `string`: The CSS to add to the page.
+func styleSheet(aOptions *TScreenshotParams, aURL string) string {
+	var sb strings.Builder
+
+	for _, name := range ssStyles.args(aURL, actionCSS) {
+		data, err := os.ReadFile(relPath(ssStyles.file(), name)) // #nosec G304
+		if nil != err {
+			log.Println(ssLibName, err)
+			continue
+		}
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+	hides := ssStyles.args(aURL, actionHide)
+	if 0 < len(aOptions.HideSelectors) {
+		hides = append(hides, aOptions.HideSelectors)
+	}
+	sb.WriteString(hideRule(hides))
+
+	return sb.String()
+} // styleSheet()
+
+// `styleTasks()` returns the browser actions adding `aStyleSheet`
+// to the current page.
+//
+// The stylesheet is added by the browser's CSS domain so it works
+// with JavaScript disabled as well.
+//
+// Parameters:
+//   - `aStyleSheet`: The CSS to add to the page.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform after loading the page.
+func styleTasks(aStyleSheet string) chromedp.Tasks {
+	if 0 == len(strings.TrimSpace(aStyleSheet)) {
+		return nil
+	}
+
+	return chromedp.Tasks{
+		chromedp.ActionFunc(func(aContext context.Context) error {
+			tree, err := page.GetFrameTree().Do(aContext)
+			if nil != err {
+				return err
+			}
+			id, err := css.CreateStyleSheet(tree.Frame.ID).Do(aContext)
+			if nil != err {
+				return err
+			}
+			_, err = css.SetStyleSheetText(id, aStyleSheet).Do(aContext)
+
+			return err
+		}),
+	}
+} // styleTasks()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `HideSelectors()` returns the CSS selectors of the elements to hide
+// on all pages.
+//
+// Returns:
+//   - `string`: The comma separated list of CSS selectors.
+func HideSelectors() string {
+	return ssOptions.HideSelectors
+} // HideSelectors()
+
+// `SetHideSelectors()` configures the CSS selectors of the elements
+// to hide on all pages in addition to the `hide` rules of the styles
+// file (see [SetStylesFile]).
+//
+// This is mostly useful for per-host capture profiles
+// (see [SetProfilesFile]).
+//
+// Parameters:
+//   - `aSelectors`: The comma separated list of CSS selectors.
+func SetHideSelectors(aSelectors string) {
+	ssOptions.HideSelectors = strings.TrimSpace(aSelectors)
+} // SetHideSelectors()
+
+// `StylesFile()` returns the name of the file listing the user
+// stylesheets and the elements to hide during page processing.
+//
+// Returns:
+//   - `string`: The path/file name of the styles file.
+func StylesFile() string {
+	return ssOptions.StylesFile
+} // StylesFile()
+
+// `SetStylesFile()` configures the name of the file listing the user
+// stylesheets and the elements to hide during page processing.
+//
+// Each line of that file consists of a host/domain (or `*` for all
+// hosts), the keyword `css` or `hide`, and either the name of a CSS
+// file or a CSS selector:
+//
+//	example.com  hide  header.sticky
+//	example.com  css   example.css
+//	*            hide  .cookie-banner, #newsletter-modal
+//
+// `css` adds the given stylesheet to the page, `hide` hides all elements
+// matching the given selector (e.g. cookie banners, sticky headers or
+// chat widgets) before the screenshot is taken.
+//
+// NOTE: This works regardless of the page's JavaScript setting.
+// An invalid filename disables the feature.
+//
+// Parameters:
+//   - `aFilename`: The path/file name of the styles file.
+func SetStylesFile(aFilename string) {
+	ssOptions.StylesFile = ssStyles.setFile(aFilename)
+} // SetStylesFile()
+
+/* _EoF_ */
@@ -0,0 +1,73 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_hideRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		aSelectors []string
+		want       string
+	}{
+		{"1", nil, ""},
+		{"2", []string{".banner"}, ".banner {\n\tdisplay: none !important;\n}\n"},
+		{"3", []string{".banner", "#chat, .modal"}, ".banner,\n#chat, .modal {\n\tdisplay: none !important;\n}\n"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hideRule(tt.aSelectors); got != tt.want {
+				t.Errorf("%q: hideRule() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_hideRule()
+
+func Test_styleSheet(t *testing.T) {
+	const (
+		fName = "./Crash_Test_Dummies.styles"
+		cName = "./Crash_Test_Dummies.css"
+	)
+	rules := `
+*	hide	.cookie-banner
+example.com	hide	header.Sticky
+example.com	css	Crash_Test_Dummies.css
+`
+	writeFile(fName, []byte(rules), nil)
+	writeFile(cName, []byte("body { margin: 0; }"), nil)
+	defer func() {
+		_ = os.Remove(fName)
+		_ = os.Remove(cName)
+		SetStylesFile("")
+	}()
+	SetStylesFile(fName)
+
+	tests := []struct {
+		name string
+		aURL string
+		want string
+	}{
+		{"1", "https://example.org/", ".cookie-banner {\n\tdisplay: none !important;\n}\n"},
+		{"2", "https://www.example.com/", "body { margin: 0; }\n.cookie-banner,\nheader.Sticky {\n\tdisplay: none !important;\n}\n"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := styleSheet(ssOptions, tt.aURL); got != tt.want {
+				t.Errorf("%q: styleSheet() = %q,\nwant %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_styleSheet()
+
+/* _EoF_ */
@@ -0,0 +1,652 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const (
+	// Max. time to wait for the browser's answer to a health check:
+	healthTimeout = 5 * time.Second
+
+	// Max. number of idle browsers kept running for other configurations:
+	maxIdleBrowsers = 3
+)
+
+type (
+	// `tBrowser` is a single browser reused for several captures.
+	tBrowser struct {
+		// Function to release the browser's allocator:
+		allocCancel context.CancelFunc
+
+		// Function to release the browser:
+		cancel context.CancelFunc
+
+		// Number of captures handled by the browser:
+		captures int
+
+		// The browser's context (`nil` if not running):
+		ctx context.Context
+
+		// Whether the browser was retired because it crashed, hung,
+		// or lost its connection:
+		failed bool
+
+		// Serialises the health checks of the browser:
+		health sync.Mutex
+
+		// Number of tabs currently processing a capture:
+		inUse int
+
+		// The configuration the browser was started with:
+		key string
+
+		// Time the browser was last released by a capture:
+		lastUsed time.Time
+
+		// Closed when the browser's start is finished:
+		ready chan struct{}
+
+		// Whether the browser is stopped once its tabs are released:
+		retired bool
+
+		// Whether the browser is still being started:
+		starting bool
+	}
+
+	// `tBrowserKey` is the context key of a tab's browser.
+	tBrowserKey struct{}
+
+	// `tSupervisor` manages the browsers reused for several captures
+	// (see [SetBrowserReuse]), one for each browser configuration.
+	//
+	// A browser which crashed, hangs, or is recycled is retired: it
+	// doesn't get new captures and is stopped as soon as the captures
+	// still running in its tabs are done.
+	//
+	// The supervisor's lock guards the list of browsers and their
+	// bookkeeping only; launching a browser and checking its health
+	// are done without holding it.
+	tSupervisor struct {
+		sync.Mutex
+
+		// The running browsers:
+		browsers []*tBrowser
+	}
+)
+
+var (
+	// ErrBrowserFailure is returned (joined with the underlying error)
+	// for captures which failed because the browser crashed, hung or
+	// lost its connection; the browser is restarted with the next
+	// capture, so the failed capture can be retried.
+	ErrBrowserFailure = errors.New(ssLibName + ": browser failure")
+
+	// The supervisor of the reused browser:
+	ssSupervisor tSupervisor
+)
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `processRSS()` returns the resident memory (in bytes) used by the
+// process `aPID` and all its descendants.
+//
+// NOTE: This function relies on Linux' `/proc` filesystem; on other
+// systems it always returns `0`.
+//
+// Parameters:
+//   - `aPID`: The ID of the process to check.
+//
+// Returns:
+//   - `int64`: The number of bytes used.
+func processRSS(aPID int) (rSize int64) {
+	stats, _ := filepath.Glob("/proc/[0-9]*/stat")
+	children := make(map[int][]int, len(stats))
+	for _, name := range stats {
+		data, err := os.ReadFile(name) // #nosec G304
+		if nil != err {
+			continue
+		}
+		// The process name (in parentheses) might contain spaces:
+		idx := strings.LastIndexByte(string(data), ')')
+		if 0 > idx {
+			continue
+		}
+		fields := strings.Fields(string(data[idx+1:]))
+		if 2 > len(fields) {
+			continue
+		}
+		pid, _ := strconv.Atoi(filepath.Base(filepath.Dir(name)))
+		ppid, _ := strconv.Atoi(fields[1])
+		children[ppid] = append(children[ppid], pid)
+	}
+
+	for pids := []int{aPID}; 0 < len(pids); {
+		pid := pids[0]
+		pids = append(pids[1:], children[pid]...)
+		rSize += vmRSS(pid)
+	}
+
+	return
+} // processRSS()
+
+// `tabOptions()` returns the options for a new browser tab processing
+// the web page `aURL` in an already running browser.
+//
+// Unless a persistent profile is used the tab is isolated in a new
+// browser context which is disposed afterwards.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `[]chromedp.ContextOption`: The options to use.
+func tabOptions(aURL string) []chromedp.ContextOption {
+	if 0 < len(profileDir(aURL)) {
+		return nil
+	}
+
+	return []chromedp.ContextOption{
+		chromedp.WithNewBrowserContext(
+			func(aParams *target.CreateBrowserContextParams) *target.CreateBrowserContextParams {
+				if proxy := proxyServer(aURL); 0 < len(proxy) {
+					aParams = aParams.WithProxyServer(proxy).
+						WithProxyBypassList(bypassList())
+				}
+				return aParams
+			}),
+	}
+} // tabOptions()
+
+// `vmRSS()` returns the resident memory (in bytes) used by the
+// process `aPID`.
+//
+// Parameters:
+//   - `aPID`: The ID of the process to check.
+//
+// Returns:
+//   - `int64`: The number of bytes used.
+func vmRSS(aPID int) int64 {
+	file, err := os.Open("/proc/" + strconv.Itoa(aPID) + "/status") // #nosec G304
+	if nil != err {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "VmRSS:"); ok {
+			// the value is given in kB:
+			fields := strings.Fields(value)
+			if 0 < len(fields) {
+				size, _ := strconv.ParseInt(fields[0], 10, 64)
+				return size << 10
+			}
+		}
+	}
+
+	return 0
+} // vmRSS()
+
+// `alive()` returns whether the browser is running and responsive.
+//
+// NOTE: The browser's start must be finished.
+//
+// Returns:
+//   - `bool`: Whether the browser is healthy.
+func (br *tBrowser) alive() bool {
+	br.health.Lock()
+	defer br.health.Unlock()
+
+	if (nil == br.ctx) || (nil != br.ctx.Err()) {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(br.ctx, healthTimeout)
+	defer cancel()
+
+	// Evaluating an expression in the browser's initial tab
+	// checks both the websocket connection and the target:
+	var result int
+	err := chromedp.Run(ctx, chromedp.Evaluate(`1`, &result))
+
+	return (nil == err) && (1 == result)
+} // alive()
+
+// `exhausted()` returns whether the browser should be recycled
+// according to the [BrowserRecycle] and [BrowserMaxRSS] settings.
+//
+// Returns:
+//   - `bool`: Whether to restart the browser.
+func (br *tBrowser) exhausted() bool {
+	if (0 < ssOptions.BrowserRecycle) && (br.captures >= ssOptions.BrowserRecycle) {
+		return true
+	}
+	if (0 < ssOptions.BrowserMaxRSS) && (nil != br.ctx) {
+		if c := chromedp.FromContext(br.ctx); (nil != c) && (nil != c.Browser) {
+			if process := c.Browser.Process(); nil != process {
+				return processRSS(process.Pid) > int64(ssOptions.BrowserMaxRSS)<<20
+			}
+		}
+	}
+
+	return false
+} // exhausted()
+
+// `start()` launches (or connects to) the browser to process the web
+// page `aURL`.
+//
+// Parameters:
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `error`: A possible error starting the browser.
+func (br *tBrowser) start(aURL string) error {
+	if 0 < len(ssOptions.RemoteURL) {
+		br.ctx, br.allocCancel = chromedp.NewRemoteAllocator(context.Background(), ssOptions.RemoteURL)
+	} else {
+		br.ctx, br.allocCancel = chromedp.NewExecAllocator(context.Background(), execOptions(aURL)...)
+	}
+	br.ctx, br.cancel = chromedp.NewContext(br.ctx, chromedp.WithLogf(log.Printf))
+
+	// An empty run starts the browser:
+	if err := chromedp.Run(br.ctx); nil != err {
+		br.stop()
+		return err
+	}
+
+	return nil
+} // start()
+
+// `stop()` terminates the browser.
+//
+// The (then cancelled) context is kept for health checks still
+// running concurrently.
+func (br *tBrowser) stop() {
+	if nil != br.cancel {
+		br.cancel()
+	}
+	if nil != br.allocCancel {
+		br.allocCancel()
+	}
+	br.allocCancel, br.cancel = nil, nil
+} // stop()
+
+// `acquire()` returns a healthy browser with the configuration `aKey`
+// and marks one of its tabs as used, starting a browser if necessary.
+//
+// Only one browser per configuration is started at a time: other
+// captures needing it wait until it's ready. An exhausted or
+// unresponsive browser is retired (see `retire()`) and replaced.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aKey`: The browser configuration to use.
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `*tBrowser`: The browser to use.
+//   - `error`: A possible error starting the browser.
+func (sv *tSupervisor) acquire(aContext context.Context, aKey, aURL string) (*tBrowser, error) {
+	for {
+		sv.Lock()
+		var browser *tBrowser
+		for _, b := range sv.browsers {
+			if (aKey == b.key) && !b.retired {
+				browser = b
+				break
+			}
+		}
+		if nil == browser {
+			browser = &tBrowser{
+				captures: 1,
+				inUse:    1,
+				key:      aKey,
+				ready:    make(chan struct{}),
+				starting: true,
+			}
+			sv.browsers = append(sv.browsers, browser)
+			sv.Unlock()
+
+			// Launch the browser without blocking the other captures:
+			err := browser.start(aURL)
+			close(browser.ready)
+
+			sv.Lock()
+			browser.starting = false
+			if nil != err {
+				browser.failed = true
+				browser.inUse--
+				sv.retire(browser)
+			} else {
+				sv.shrink(aKey)
+			}
+			sv.Unlock()
+
+			if nil != err {
+				return nil, errors.Join(ErrBrowserFailure, err)
+			}
+			return browser, nil
+		}
+		sv.Unlock()
+
+		// Wait for a browser being started by another capture:
+		select {
+		case <-browser.ready:
+		case <-aContext.Done():
+			return nil, aContext.Err()
+		}
+
+		sv.Lock()
+		if !browser.retired && browser.exhausted() {
+			sv.retire(browser)
+		}
+		retired := browser.retired
+		sv.Unlock()
+		if retired {
+			continue
+		}
+
+		alive := browser.alive()
+
+		sv.Lock()
+		if browser.retired {
+			sv.Unlock()
+			continue
+		}
+		if !alive {
+			browser.failed = true
+			sv.retire(browser)
+			sv.Unlock()
+			continue
+		}
+		browser.captures++
+		browser.inUse++
+		sv.Unlock()
+
+		return browser, nil
+	}
+} // acquire()
+
+// `check()` checks the browser of the tab `aTab` after the capture
+// error `aErr` and retires the browser if it isn't healthy anymore.
+//
+// The captures running in other tabs of that browser are left alone
+// but their errors are wrapped as well once they fail.
+//
+// Parameters:
+//   - `aTab`: The context of the tab whose capture failed.
+//   - `aErr`: The error of the failed capture.
+//
+// Returns:
+//   - `error`: The (possibly [ErrBrowserFailure] wrapped) error.
+func (sv *tSupervisor) check(aTab context.Context, aErr error) error {
+	browser, ok := aTab.Value(tBrowserKey{}).(*tBrowser)
+	if !ok {
+		return aErr
+	}
+
+	sv.Lock()
+	failed := browser.failed
+	sv.Unlock()
+
+	if !failed {
+		// A browser retired for recycling may still be healthy:
+		if browser.alive() {
+			return aErr
+		}
+
+		sv.Lock()
+		if !browser.failed {
+			log.Println(ssLibName, "browser failure:", aErr)
+			browser.failed = true
+			sv.retire(browser)
+		}
+		sv.Unlock()
+	}
+
+	return errors.Join(ErrBrowserFailure, aErr)
+} // check()
+
+// `release()` marks a tab of `aBrowser` as done, stopping the browser
+// if it's retired and this was its last tab.
+//
+// Parameters:
+//   - `aBrowser`: The browser whose tab was closed.
+func (sv *tSupervisor) release(aBrowser *tBrowser) {
+	sv.Lock()
+	defer sv.Unlock()
+
+	aBrowser.inUse--
+	aBrowser.lastUsed = time.Now()
+	if aBrowser.retired && (0 >= aBrowser.inUse) {
+		aBrowser.stop()
+		sv.remove(aBrowser)
+	}
+} // release()
+
+// `remove()` removes `aBrowser` from the list of running browsers.
+//
+// NOTE: The caller must hold the supervisor's lock.
+//
+// Parameters:
+//   - `aBrowser`: The browser to remove.
+func (sv *tSupervisor) remove(aBrowser *tBrowser) {
+	sv.browsers = slices.DeleteFunc(sv.browsers, func(aItem *tBrowser) bool {
+		return aItem == aBrowser
+	})
+} // remove()
+
+// `retire()` excludes `aBrowser` from further captures, stopping it
+// right away if no capture is using it.
+//
+// NOTE: The caller must hold the supervisor's lock.
+//
+// Parameters:
+//   - `aBrowser`: The browser to retire.
+func (sv *tSupervisor) retire(aBrowser *tBrowser) {
+	aBrowser.retired = true
+	if 0 >= aBrowser.inUse {
+		aBrowser.stop()
+		sv.remove(aBrowser)
+	}
+} // retire()
+
+// `shrink()` retires the least recently used idle browsers of other
+// configurations than `aKey` if there are more than [maxIdleBrowsers].
+//
+// NOTE: The caller must hold the supervisor's lock.
+//
+// Parameters:
+//   - `aKey`: The configuration of the browser to keep.
+func (sv *tSupervisor) shrink(aKey string) {
+	var idle []*tBrowser
+	for _, browser := range sv.browsers {
+		if (aKey != browser.key) && !browser.retired && (0 >= browser.inUse) {
+			idle = append(idle, browser)
+		}
+	}
+	if len(idle) <= maxIdleBrowsers {
+		return
+	}
+	slices.SortFunc(idle, func(a, b *tBrowser) int {
+		return a.lastUsed.Compare(b.lastUsed)
+	})
+	for _, browser := range idle[:len(idle)-maxIdleBrowsers] {
+		sv.retire(browser)
+	}
+} // shrink()
+
+// `tab()` returns a new tab of the reused browser for processing the
+// web page `aURL`, starting a browser if necessary (see `acquire()`).
+//
+// Each browser configuration (like a per-host profile or proxy) gets
+// a browser of its own. An exhausted or unresponsive browser is
+// retired (see `retire()`) and replaced by a new one without
+// affecting the captures still running in its tabs.
+//
+// Parameters:
+//   - `aContext`: The active context to use.
+//   - `aURL`: The address of the web page to process.
+//
+// Returns:
+//   - `context.Context`: The tab's context.
+//   - `context.CancelFunc`: The function to close the tab.
+//   - `error`: A possible error starting the browser.
+func (sv *tSupervisor) tab(aContext context.Context, aURL string) (context.Context, context.CancelFunc, error) {
+	// A different configuration requires a different browser process:
+	key := strings.Join([]string{ssOptions.RemoteURL, ssOptions.ExecPath,
+		ssOptions.ChromeFlags, strconv.FormatBool(ssOptions.Headless),
+		profileDir(aURL), proxyServer(aURL)}, "\n")
+
+	browser, err := sv.acquire(aContext, key, aURL)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	ctx, cancel := chromedp.NewContext(
+		context.WithValue(browser.ctx, tBrowserKey{}, browser),
+		tabOptions(aURL)...)
+	// Close the tab when the caller's context is done:
+	stop := context.AfterFunc(aContext, cancel)
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			stop()
+			cancel()
+			sv.release(browser)
+		})
+	}, nil
+} // tab()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `BrowserMaxRSS()` returns the memory limit (in MB) of the reused
+// browser.
+//
+// Returns:
+//   - `int`: The max. resident memory of the browser.
+func BrowserMaxRSS() int {
+	return ssOptions.BrowserMaxRSS
+} // BrowserMaxRSS()
+
+// `SetBrowserMaxRSS()` configures the memory limit (in MB) of the
+// reused browser (see [SetBrowserReuse]).
+//
+// If the resident memory of the browser and all its child processes
+// exceeds that limit the browser is restarted before the next
+// capture. `0` disables the limit.
+//
+// NOTE: This check is available under Linux only.
+//
+// Parameters:
+//   - `aMegaBytes`: The max. resident memory of the browser.
+func SetBrowserMaxRSS(aMegaBytes int) {
+	if 0 > aMegaBytes {
+		aMegaBytes = 0
+	}
+	ssOptions.BrowserMaxRSS = aMegaBytes
+} // SetBrowserMaxRSS()
+
+// `BrowserRecycle()` returns the number of captures after which the
+// reused browser is restarted.
+//
+// Returns:
+//   - `int`: The number of captures per browser.
+func BrowserRecycle() int {
+	return ssOptions.BrowserRecycle
+} // BrowserRecycle()
+
+// `SetBrowserRecycle()` configures the number of captures after which
+// the reused browser (see [SetBrowserReuse]) is restarted to free the
+// resources it might have leaked.
+// `0` disables recycling.
+//
+// Parameters:
+//   - `aCaptures`: The number of captures per browser.
+func SetBrowserRecycle(aCaptures int) {
+	if 0 > aCaptures {
+		aCaptures = 0
+	}
+	ssOptions.BrowserRecycle = aCaptures
+} // SetBrowserRecycle()
+
+// `BrowserReuse()` returns whether running browsers are reused for
+// the captures.
+//
+// Returns:
+//   - `bool`: Whether to reuse the browser.
+func BrowserReuse() bool {
+	return ssOptions.BrowserReuse
+} // BrowserReuse()
+
+// `SetBrowserReuse()` configures whether running browsers are reused
+// for the captures instead of launching a new one for each capture
+// (the default).
+//
+// Reusing a browser saves its start-up time for each capture.
+// Captures requiring a different browser configuration (like a
+// per-host profile or proxy) get a browser of their own.
+// The browsers are supervised: each is checked before a capture and
+// replaced transparently if it crashed, hangs, or lost its
+// connection, and it's recycled according to the [BrowserRecycle]
+// and [BrowserMaxRSS] settings. A replaced browser is stopped only
+// after the captures still running in its tabs are done. All
+// captures failing because of such a browser failure return an
+// error matching [ErrBrowserFailure] and can be retried.
+// Unless a persistent profile is used (see [ProfileMode]) each capture
+// gets its own, disposable browser context.
+//
+// Use [CloseBrowser] to terminate the browsers when you're done.
+//
+// Parameters:
+//   - `doReuse`: Whether to reuse the browser.
+func SetBrowserReuse(doReuse bool) {
+	if ssOptions.BrowserReuse = doReuse; !doReuse {
+		CloseBrowser()
+	}
+} // SetBrowserReuse()
+
+// `CloseBrowser()` terminates the reused browsers (if any).
+//
+// A browser still being started is terminated once the capture
+// waiting for it is done.
+// A later capture starts a new browser if [BrowserReuse] is active.
+func CloseBrowser() {
+	ssSupervisor.Lock()
+	defer ssSupervisor.Unlock()
+
+	for _, browser := range ssSupervisor.browsers {
+		browser.retired = true
+		if !browser.starting {
+			browser.stop()
+		}
+	}
+	ssSupervisor.browsers = nil
+} // CloseBrowser()
+
+/* _EoF_ */
@@ -0,0 +1,213 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+// `fakeBrowser()` returns a "running" browser whose `stop()` increments
+// `aStopped`.
+func fakeBrowser(aKey string, aInUse int, aStopped *int) *tBrowser {
+	return &tBrowser{
+		cancel: func() { *aStopped++ },
+		inUse:  aInUse,
+		key:    aKey,
+	}
+} // fakeBrowser()
+
+func Test_processRSS(t *testing.T) {
+	if _, err := os.Stat("/proc/self/status"); nil != err {
+		t.Skip("no /proc filesystem available")
+	}
+
+	tests := []struct {
+		name    string
+		aPID    int
+		wantMin int64
+		wantMax int64
+	}{
+		{"1", os.Getpid(), 1, 1 << 40},
+		{"2", -1, 0, 0},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := processRSS(tt.aPID); (got < tt.wantMin) || (got > tt.wantMax) {
+				t.Errorf("%q: processRSS() = %d, want %d … %d",
+					tt.name, got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+} // Test_processRSS()
+
+func Test_tBrowser_exhausted(t *testing.T) {
+	defer func(aRecycle, aMaxRSS int) {
+		SetBrowserRecycle(aRecycle)
+		SetBrowserMaxRSS(aMaxRSS)
+	}(BrowserRecycle(), BrowserMaxRSS())
+
+	tests := []struct {
+		name      string
+		aRecycle  int
+		aMaxRSS   int
+		aCaptures int
+		want      bool
+	}{
+		{"1", 0, 0, 1000, false},
+		{"2", 10, 0, 9, false},
+		{"3", 10, 0, 10, true},
+		{"4", -5, 0, 10, false},
+		{"5", 0, 1, 10, false}, // no browser running
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetBrowserRecycle(tt.aRecycle)
+			SetBrowserMaxRSS(tt.aMaxRSS)
+			br := &tBrowser{captures: tt.aCaptures}
+			if got := br.exhausted(); got != tt.want {
+				t.Errorf("%q: tBrowser.exhausted() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_tBrowser_exhausted()
+
+func Test_tSupervisor_acquire(t *testing.T) {
+	// A browser being started by another capture:
+	starting := &tBrowser{
+		inUse:    1,
+		key:      "a",
+		ready:    make(chan struct{}),
+		starting: true,
+	}
+	sv := &tSupervisor{browsers: []*tBrowser{starting}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// The capture must wait for that browser instead of starting
+	// another one:
+	if _, err := sv.acquire(ctx, "a", "https://example.com/"); !errors.Is(err, context.Canceled) {
+		t.Errorf("tSupervisor.acquire() error = %v, want %v",
+			err, context.Canceled)
+	}
+	if got := len(sv.browsers); 1 != got {
+		t.Errorf("tSupervisor.acquire() browsers = %d, want 1", got)
+	}
+	if 1 != starting.inUse {
+		t.Errorf("tSupervisor.acquire() inUse = %d, want 1", starting.inUse)
+	}
+} // Test_tSupervisor_acquire()
+
+func Test_tSupervisor_check(t *testing.T) {
+	var stopped int
+	sv := &tSupervisor{}
+	browser := fakeBrowser("a", 3, &stopped) // i.e. not responding
+	sv.browsers = []*tBrowser{browser}
+	tab := context.WithValue(context.Background(), tBrowserKey{}, browser)
+	errTab := errors.New("websocket closed")
+
+	tests := []struct {
+		name string
+		aTab context.Context
+		want bool
+	}{
+		{"1", context.Background(), false},
+		{"2", tab, true},
+		{"3", tab, true}, // another tab of the failed browser
+		{"4", tab, true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sv.check(tt.aTab, errTab)
+			if got := errors.Is(err, ErrBrowserFailure); got != tt.want {
+				t.Errorf("%q: tSupervisor.check() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if !errors.Is(err, errTab) {
+				t.Errorf("%q: tSupervisor.check() lost the capture's error", tt.name)
+			}
+		})
+	}
+
+	if !browser.failed || !browser.retired {
+		t.Error("tSupervisor.check() didn't retire the failed browser")
+	}
+	if 0 != stopped {
+		t.Errorf("tSupervisor.check() stopped = %d, want 0", stopped)
+	}
+} // Test_tSupervisor_check()
+
+func Test_tSupervisor_retire(t *testing.T) {
+	tests := []struct {
+		name        string
+		aInUse      int
+		aReleases   int
+		wantStopped int
+		wantCount   int
+	}{
+		{"1", 0, 0, 1, 1},
+		{"2", 2, 0, 0, 2},
+		{"3", 2, 1, 0, 2},
+		{"4", 2, 2, 1, 1},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stopped, other int
+			sv := &tSupervisor{}
+			browser := fakeBrowser("a", tt.aInUse, &stopped)
+			sv.browsers = []*tBrowser{browser, fakeBrowser("b", 1, &other)}
+
+			sv.retire(browser)
+			for range tt.aReleases {
+				sv.release(browser)
+			}
+			if stopped != tt.wantStopped {
+				t.Errorf("%q: tSupervisor.retire() stopped = %d, want %d",
+					tt.name, stopped, tt.wantStopped)
+			}
+			if got := len(sv.browsers); got != tt.wantCount {
+				t.Errorf("%q: tSupervisor.retire() browsers = %d, want %d",
+					tt.name, got, tt.wantCount)
+			}
+			if 0 != other {
+				t.Errorf("%q: tSupervisor.retire() stopped another browser", tt.name)
+			}
+		})
+	}
+} // Test_tSupervisor_retire()
+
+func Test_tSupervisor_shrink(t *testing.T) {
+	var stopped int
+	sv := &tSupervisor{}
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		sv.browsers = append(sv.browsers, fakeBrowser(key, 0, &stopped))
+	}
+	sv.browsers[1].inUse = 1 // "b" is busy
+
+	sv.shrink("a")
+	// "a" is kept, "b" is busy, and of the idle "c" … "f" the
+	// least recently used "c" is stopped:
+	if 1 != stopped {
+		t.Errorf("tSupervisor.shrink() stopped = %d, want 1", stopped)
+	}
+	var keys string
+	for _, browser := range sv.browsers {
+		keys += browser.key
+	}
+	if "abdef" != keys {
+		t.Errorf("tSupervisor.shrink() = %q, want %q", keys, "abdef")
+	}
+} // Test_tSupervisor_shrink()
+
+/* _EoF_ */
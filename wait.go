@@ -0,0 +1,95 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+			All rights reserved
+		EMail : <support@mwat.de>
+*/
+
+package screenshot
+
+import (
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// --------------------------------------------------------------------------
+/*                           private functions                             */
+
+// `waitTasks()` returns the browser actions waiting for the page to
+// be received and rendered.
+//
+// If a [WaitSelector] is configured the actions wait for a matching
+// element to become visible before waiting the [WaitTime].
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//   - `aEnableJS`: Whether JavaScript is active in the browser.
+//
+// Returns:
+//   - `chromedp.Tasks`: The actions to perform after loading the page.
+func waitTasks(aOptions *TScreenshotParams, aEnableJS bool) chromedp.Tasks {
+	var tasks chromedp.Tasks
+	if 0 < len(aOptions.WaitSelector) {
+		tasks = append(tasks,
+			chromedp.WaitVisible(aOptions.WaitSelector, chromedp.ByQuery),
+		)
+	}
+
+	return append(tasks,
+		chromedp.Sleep(waitTime(aOptions, aEnableJS)), // time to receive&render the page
+	)
+} // waitTasks()
+
+// --------------------------------------------------------------------------
+/*                           public functions                              */
+
+// `WaitSelector()` returns the CSS selector of the element to wait
+// for before taking the screenshot.
+//
+// Returns:
+//   - `string`: The CSS selector.
+func WaitSelector() string {
+	return ssOptions.WaitSelector
+} // WaitSelector()
+
+// `SetWaitSelector()` configures the CSS selector of an element to
+// wait for (i.e. until it's visible) before taking the screenshot,
+// e.g. the main content of a page rendered by JavaScript.
+//
+// If no such element appears within the [MaxProcessTime] the capture
+// fails with a timeout. An empty selector disables the feature.
+//
+// Parameters:
+//   - `aSelector`: The CSS selector of the element to wait for.
+func SetWaitSelector(aSelector string) {
+	ssOptions.WaitSelector = strings.TrimSpace(aSelector)
+} // SetWaitSelector()
+
+// `WaitTime()` returns the time (in milliseconds) to wait for a page
+// to be received and rendered.
+//
+// Returns:
+//   - `int`: The wait time (`0` for the default).
+func WaitTime() int {
+	return ssOptions.WaitTime
+} // WaitTime()
+
+// `SetWaitTime()` configures the time (in milliseconds) to wait for a
+// page to be received and rendered before taking the screenshot.
+//
+// The default `0` waits two seconds, or four seconds if JavaScript
+// is active for the page.
+//
+// Parameters:
+//   - `aMilliseconds`: The wait time.
+func SetWaitTime(aMilliseconds int) {
+	if 0 > aMilliseconds {
+		aMilliseconds = 0
+	}
+	ssOptions.WaitTime = aMilliseconds
+} // SetWaitTime()
+
+/* _EoF_ */